@@ -22,18 +22,26 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/uberswe/LoopiaDomainBackorder/internal/available"
 	"github.com/uberswe/LoopiaDomainBackorder/internal/dropcatch"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/clock"
 	"github.com/uberswe/LoopiaDomainBackorder/pkg/config"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/fixture"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/metrics"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/ntp"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/shutdown"
 )
 
 // Version information
@@ -46,9 +54,31 @@ var (
 	BuildDate = "unknown"
 )
 
-// setupLogging configures zerolog to write logs to both console and file
-// It creates a new log file for each day and cleans up log files older than 30 days
-func setupLogging() error {
+// shutdownTimeout bounds how long registered subsystems get to clean up
+// after a shutdown signal before the process exits anyway.
+const shutdownTimeout = 10 * time.Second
+
+// stringSlice accumulates repeated occurrences of a flag, e.g.
+// -config a.yml -config b.yml, so config files can be merged in order.
+type stringSlice []string
+
+func (s *stringSlice) String() string     { return strings.Join(*s, ",") }
+func (s *stringSlice) Set(v string) error { *s = append(*s, v); return nil }
+
+// resolveConfigFiles returns files, or the default config file name if no
+// -config flag was given.
+func resolveConfigFiles(files stringSlice) []string {
+	if len(files) == 0 {
+		return []string{config.DefaultConfigFileName}
+	}
+	return files
+}
+
+// setupLogging configures zerolog to write logs to both console and file.
+// It creates a new log file for each day and cleans up log files older than
+// 30 days, returning the opened file so the caller can flush/close it on
+// shutdown.
+func setupLogging() (*os.File, error) {
 	// Set global time format to include microseconds
 	zerolog.TimeFieldFormat = "2006-01-02 15:04:05.000000"
 
@@ -58,7 +88,7 @@ func setupLogging() error {
 	// Create log directory if it doesn't exist
 	logDir := "logs"
 	if err := os.MkdirAll(logDir, 0755); err != nil {
-		return fmt.Errorf("failed to create log directory: %w", err)
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
 	}
 
 	// Clean up old log files
@@ -74,7 +104,7 @@ func setupLogging() error {
 	logFileName := filepath.Join(logDir, time.Now().Format("2006-01-02")+".log")
 	logFile, err := os.OpenFile(logFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
-		return fmt.Errorf("failed to open log file: %w", err)
+		return nil, fmt.Errorf("failed to open log file: %w", err)
 	}
 
 	// Use MultiLevelWriter to write to both console and file
@@ -82,7 +112,7 @@ func setupLogging() error {
 	log.Logger = zerolog.New(multi).With().Timestamp().Caller().Logger()
 
 	log.Info().Str("file", logFileName).Msg("Logging to file initialized")
-	return nil
+	return logFile, nil
 }
 
 // cleanupOldLogs removes log files older than the specified number of days
@@ -123,13 +153,53 @@ func cleanupOldLogs(logDir string, maxAgeDays int) {
 	}
 }
 
+// syncedClock queries servers over SNTP (pkg/ntp) for the local clock's skew
+// against true time and returns a clock.Clock that corrects for it, so the
+// dropcatch wait loop fires against synced time instead of a potentially
+// skewed local clock. It exits the process if the measured offset's
+// magnitude exceeds maxSkew, unless force is set.
+func syncedClock(servers []string, maxSkew time.Duration, force bool) clock.Clock {
+	if len(servers) == 0 {
+		servers = ntp.DefaultServers
+	}
+
+	offset, err := ntp.Sync(servers, 2*time.Second)
+	if err != nil {
+		log.Warn().Err(err).Msg("NTP sync failed, firing against the unsynced local clock")
+		return clock.NewSystemClock()
+	}
+
+	abs := offset
+	if abs < 0 {
+		abs = -abs
+	}
+	log.Info().Dur("offset", offset).Strs("servers", servers).Msg("NTP clock-skew offset measured")
+
+	if abs > maxSkew && !force {
+		log.Fatal().
+			Dur("offset", offset).
+			Dur("max_skew", maxSkew).
+			Msg("Measured NTP clock-skew offset exceeds -max-skew, refusing to fire (use -force to override)")
+	}
+
+	return clock.NewOffsetClock(clock.NewSystemClock(), offset)
+}
+
 func main() {
 	// Setup logging with file output and rotation
-	if err := setupLogging(); err != nil {
+	logFile, err := setupLogging()
+	if err != nil {
 		fmt.Printf("Error setting up logging: %v\n", err)
 		os.Exit(1)
 	}
 
+	// closers collects subsystems (the Loopia API client, the registrar, the
+	// log file) that need a bounded chance to clean up on shutdown; see
+	// shutdownTimeout below.
+	closers := shutdown.NewRegistry()
+	closers.Register("log-file", shutdown.CloserFunc(func(context.Context) error { return logFile.Close() }))
+	defer closers.Close(context.Background(), shutdownTimeout)
+
 	// Check if we have any arguments
 	if len(os.Args) < 2 {
 		fmt.Println("Usage: loopiaDomainGrabber <command> [options]")
@@ -147,8 +217,9 @@ func main() {
 	// Remove the command from os.Args to make flag parsing work
 	os.Args = append(os.Args[:1], os.Args[2:]...)
 
-	// Define common flags
-	configFile := flag.String("config", config.DefaultConfigFileName, "Path to configuration file")
+	// Define common flags. -config can be repeated to merge multiple files in order.
+	var configFiles stringSlice
+	flag.Var(&configFiles, "config", "Path to configuration file (YAML or JSON; can be repeated to merge multiple files)")
 
 	// Command-specific handling
 	switch command {
@@ -158,12 +229,24 @@ func main() {
 		dry := flag.Bool("dry", false, "Dry‑run – don't hit Loopia API")
 		startNow := flag.Bool("now", false, "Start registration attempts immediately instead of waiting for drop time")
 		keepAwakeFlag := flag.Bool("keep-awake", false, "Keep computer awake by moving mouse")
+		schedule := flag.String("schedule", "", "Cron expression (e.g. \"0 4 * * *\") to run dropcatch repeatedly instead of exiting after one drop")
+		metricsAddr := flag.String("metrics-addr", ":9090", "Address to expose Prometheus metrics on (/metrics)")
+		concurrency := flag.Int("concurrency", 4, "Maximum number of domains to attempt in parallel")
+		journalPath := flag.String("journal", "", "Path to the attempt journal file (default: <cache_dir>/journal.jsonl)")
+		resetJournal := flag.Bool("reset-journal", false, "Delete the attempt journal before starting, discarding resume state")
+		var ntpServers stringSlice
+		flag.Var(&ntpServers, "ntp-server", "NTP server to query for clock-skew correction before firing (can be repeated; default pool.ntp.org, time.cloudflare.com, time.google.com)")
+		maxSkew := flag.Duration("max-skew", 100*time.Millisecond, "Refuse to fire if the measured NTP clock-skew offset exceeds this (see -force)")
+		force := flag.Bool("force", false, "Fire even if the measured NTP clock-skew offset exceeds -max-skew")
+		replayPath := flag.String("replay", "", "Replay dry-run API calls from a recorded fixture transcript instead of always returning OK (implies -dry)")
+		replayMatch := flag.String("replay-match", string(fixture.MatchExact), "How to match calls against the -replay transcript: exact, method-only, or first-arg-only")
+		recordPath := flag.String("record", "", "Record every real (non-dry) API call to a fixture transcript for later -replay")
 
 		// Parse flags
 		flag.Parse()
 
 		// Load configuration
-		cfg, err := config.Load(*configFile)
+		cfg, err := config.LoadMulti(resolveConfigFiles(configFiles))
 		if err != nil {
 			log.Fatal().Err(err).Msg("Failed to load configuration")
 		}
@@ -179,8 +262,37 @@ func main() {
 			}
 		}
 
-		// Run dropcatch command
-		dropcatch.Run(cfg, *domain, *dry, *startNow, *keepAwakeFlag)
+		// Cancel on SIGINT/SIGTERM/SIGHUP so in-flight waits and attempts can
+		// shut down cleanly instead of being killed mid-attempt.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+		defer stop()
+
+		metrics.StartServer(ctx, *metricsAddr)
+
+		clk := syncedClock(ntpServers, *maxSkew, *force)
+
+		if *replayPath != "" {
+			*dry = true
+		}
+		dropcatchOpts := []dropcatch.Option{dropcatch.WithContext(ctx), dropcatch.WithClock(clk), dropcatch.WithCloserRegistry(closers)}
+		if *replayPath != "" {
+			dropcatchOpts = append(dropcatchOpts, dropcatch.WithReplay(*replayPath, fixture.MatchStrategy(*replayMatch)))
+		}
+		if *recordPath != "" {
+			dropcatchOpts = append(dropcatchOpts, dropcatch.WithRecord(*recordPath))
+		}
+
+		if *schedule != "" && !*startNow {
+			// Run repeatedly on the given cron schedule, persisting results so
+			// restarts don't re-attempt domains already won.
+			if err := dropcatch.RunScheduled(cfg, *schedule, *dry, *concurrency, *journalPath, *resetJournal, dropcatchOpts...); err != nil {
+				log.Fatal().Err(err).Msg("Scheduled dropcatch run failed")
+			}
+			return
+		}
+
+		// Run dropcatch command (one-shot mode)
+		dropcatch.Run(cfg, *domain, *dry, *startNow, *keepAwakeFlag, *concurrency, *journalPath, *resetJournal, dropcatchOpts...)
 
 	case "available":
 		// Define available-specific flags
@@ -190,7 +302,7 @@ func main() {
 		flag.Parse()
 
 		// Load configuration
-		cfg, err := config.Load(*configFile)
+		cfg, err := config.LoadMulti(resolveConfigFiles(configFiles))
 		if err != nil {
 			log.Fatal().Err(err).Msg("Failed to load configuration")
 		}
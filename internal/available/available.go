@@ -2,220 +2,354 @@
 package available
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/uberswe/LoopiaDomainBackorder/pkg/config"
 	"github.com/uberswe/LoopiaDomainBackorder/pkg/domain"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/source"
 	"github.com/uberswe/LoopiaDomainBackorder/pkg/util"
+	"golang.org/x/sync/errgroup"
 )
 
-// URLs to download
-var domainListURLs = []string{
-	"https://data.internetstiftelsen.se/bardate_domains.txt",
-	"https://data.internetstiftelsen.se/bardate_domains_nu.txt",
+// defaultSourceConcurrency is how many sources are fetched in parallel when
+// domain.Config.SourceConcurrency is unset.
+const defaultSourceConcurrency = 4
+
+// defaultSourceConfigs is used when cfg.Sources is empty, preserving the
+// original Internetstiftelsen bardate lists as the out-of-the-box behaviour.
+func defaultSourceConfigs() []domain.SourceConfig {
+	return []domain.SourceConfig{
+		{Type: source.TypeBardate, URL: "https://data.internetstiftelsen.se/bardate_domains.txt"},
+		{Type: source.TypeBardate, URL: "https://data.internetstiftelsen.se/bardate_domains_nu.txt"},
+	}
 }
 
-// Run handles the available command functionality
-func Run(cfg *domain.Config) {
-	log.Info().Msg("Running available command to find valuable domains expiring today")
-
-	// Check if we need to download new files (cache expired)
-	needsDownload := true
-	if cfg.LastCacheTime != "" {
-		lastCache, err := time.Parse(time.RFC3339, cfg.LastCacheTime)
-		if err == nil {
-			// Check if cache is less than 24 hours old
-			if time.Since(lastCache) < 24*time.Hour {
-				needsDownload = false
-				log.Info().Time("last_cache", lastCache).Msg("Using cached domain lists (less than 24 hours old)")
-			}
-		}
+// sourceCacheMaxAge is how long a source's fetched records are reused before
+// it's queried again.
+const sourceCacheMaxAge = 24 * time.Hour
+
+// Run handles the available command functionality. dateStr is the
+// operator-supplied -date flag (format: YYYY-MM-DD); an empty string means
+// "today" (util.GetReferenceDate(time.Now())).
+func Run(cfg *domain.Config, dateStr string) {
+	if dateStr == "" {
+		log.Info().Msg("Running available command to find valuable domains expiring today")
+	} else {
+		log.Info().Str("date", dateStr).Msg("Running available command to find valuable domains expiring on the given date")
 	}
 
-	// Initialize cache map if needed
-	if cfg.CachedLists == nil {
-		cfg.CachedLists = make(map[string]string)
+	domains, err := CandidateDomains(cfg, dateStr)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to gather candidate domains")
+		return
+	}
+
+	displayTopDomains(domains)
+}
+
+// CandidateDomains fetches and scores every domain expiring on the reference
+// date (dateStr, format YYYY-MM-DD; empty means today's
+// util.GetReferenceDate) from cfg.Sources (or the default Internetstiftelsen
+// bardate lists if none are configured), returning them in canonical DNS
+// order. It does the same cache bookkeeping as Run but doesn't display or
+// print anything itself, so other commands (e.g. dropcatch's scheduled
+// daemon) can re-derive the day's candidates programmatically instead of
+// only chasing a fixed static domain list.
+func CandidateDomains(cfg *domain.Config, dateStr string) ([]domain.DomainInfo, error) {
+	referenceDate, err := parseReferenceDate(dateStr)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create cache directory if it doesn't exist
 	if cfg.CacheDir == "" {
 		cfg.CacheDir = "cache"
 	}
+	if cfg.SourceCacheTimes == nil {
+		cfg.SourceCacheTimes = make(map[string]string)
+	}
+	if cfg.SourceHTTPCache == nil {
+		cfg.SourceHTTPCache = make(map[string]domain.HTTPCacheMeta)
+	}
+
+	if err := os.MkdirAll(cfg.CacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating cache directory %s: %w", cfg.CacheDir, err)
+	}
 
-	err := os.MkdirAll(cfg.CacheDir, 0755)
+	sourceConfigs := cfg.Sources
+	if len(sourceConfigs) == 0 {
+		sourceConfigs = defaultSourceConfigs()
+	}
+
+	records := fetchRecords(cfg, source.FromConfig(sourceConfigs, cfg.CacheDir))
+
+	if err := config.Save(cfg, config.DefaultConfigFileName); err != nil {
+		log.Error().Err(err).Msg("Failed to save updated configuration")
+	}
+
+	return processRecords(cfg, records, referenceDate), nil
+}
+
+// parseReferenceDate resolves dateStr (format YYYY-MM-DD) to a reference
+// date, defaulting to util.GetReferenceDate(time.Now()) (today, or tomorrow
+// if past the drop cutoff) when dateStr is empty.
+func parseReferenceDate(dateStr string) (time.Time, error) {
+	if dateStr == "" {
+		return util.GetReferenceDate(time.Now()), nil
+	}
+	t, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
-		log.Error().Err(err).Str("dir", cfg.CacheDir).Msg("Failed to create cache directory")
-		return
+		return time.Time{}, fmt.Errorf("parsing -date %q: %w", dateStr, err)
 	}
+	return t, nil
+}
 
-	// Download files if needed
-	if needsDownload {
-		downloadDomainLists(cfg)
+// fetchRecords gathers DomainRecords from every src in parallel, using each
+// source's own cached copy (gated by cfg.SourceCacheTimes[src.CacheKey()])
+// when it's less than sourceCacheMaxAge old, and refreshing it from
+// src.Fetch (or, for a source.ConditionalFetcher, FetchConditional) otherwise.
+func fetchRecords(cfg *domain.Config, srcs []source.Source) []source.DomainRecord {
+	limit := cfg.SourceConcurrency
+	if limit <= 0 {
+		limit = defaultSourceConcurrency
 	}
 
-	// Process domain lists
-	domains := processDomainLists(cfg)
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(limit)
 
-	// Display top domains
-	displayTopDomains(domains)
+	var mu sync.Mutex
+	var all []source.DomainRecord
+
+	for _, src := range srcs {
+		src := src
+		g.Go(func() error {
+			fetched, err := fetchOne(ctx, cfg, src)
+			if err != nil {
+				log.Error().Err(err).Str("source", src.Name()).Msg("Failed to fetch source records")
+				return nil
+			}
+			mu.Lock()
+			all = append(all, fetched...)
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait() // the goroutines above log fetchOne's errors and return nil, so partial failures just skip that source
+
+	return all
 }
 
-// downloadDomainLists downloads the domain lists and caches them
-func downloadDomainLists(cfg *domain.Config) {
-	log.Info().Msg("Downloading domain lists")
+// cacheMu serializes reads/writes of cfg.SourceCacheTimes and
+// cfg.SourceHTTPCache across the concurrent fetchOne calls in fetchRecords.
+var cacheMu sync.Mutex
 
-	for _, url := range domainListURLs {
-		log.Info().Str("url", url).Msg("Downloading domain list")
+// fetchOne fetches (or reuses the cache for) a single source, updating
+// cfg.SourceCacheTimes/SourceHTTPCache as a side effect.
 
-		// Download file
-		resp, err := http.Get(url)
-		if err != nil {
-			log.Error().Err(err).Str("url", url).Msg("Failed to download domain list")
-			continue
+func fetchOne(ctx context.Context, cfg *domain.Config, src source.Source) ([]source.DomainRecord, error) {
+	key := src.CacheKey()
+	cacheFile := fmt.Sprintf("%s/%s.json", cfg.CacheDir, key)
+
+	cacheMu.Lock()
+	fresh := isFresh(cfg.SourceCacheTimes[key])
+	httpMeta := cfg.SourceHTTPCache[key]
+	cacheMu.Unlock()
+
+	if fresh {
+		if cached, err := readRecordCache(cacheFile); err == nil {
+			log.Info().Str("source", src.Name()).Msg("Using cached source records (less than 24 hours old)")
+			return cached, nil
 		}
-		defer resp.Body.Close()
+		log.Warn().Str("source", src.Name()).Msg("Cache marked fresh but unreadable, re-fetching")
+	}
+
+	log.Info().Str("source", src.Name()).Msg("Fetching source records")
+
+	var fetched []source.DomainRecord
+	var newMeta domain.HTTPCacheMeta
+	var notModified bool
 
-		// Read response body
-		body, err := io.ReadAll(resp.Body)
+	if cf, ok := src.(source.ConditionalFetcher); ok {
+		var err error
+		fetched, newMeta.ETag, newMeta.LastModified, notModified, err = cf.FetchConditional(ctx, httpMeta.ETag, httpMeta.LastModified)
 		if err != nil {
-			log.Error().Err(err).Str("url", url).Msg("Failed to read domain list")
-			continue
+			return nil, err
 		}
-
-		// Save to cache
-		filename := fmt.Sprintf("%s/%s", cfg.CacheDir, filepath.Base(url))
-		err = os.WriteFile(filename, body, 0644)
+	} else {
+		var err error
+		fetched, err = src.Fetch(ctx)
 		if err != nil {
-			log.Error().Err(err).Str("file", filename).Msg("Failed to save domain list to cache")
-			continue
+			return nil, err
 		}
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
 
-		// Update cache map
-		cfg.CachedLists[url] = filename
-		log.Info().Str("url", url).Str("file", filename).Msg("Domain list cached")
+	if notModified {
+		log.Info().Str("source", src.Name()).Msg("Source unchanged since last fetch (304), reusing cache")
+		cfg.SourceCacheTimes[key] = time.Now().Format(time.RFC3339)
+		cfg.SourceHTTPCache[key] = newMeta
+		return readRecordCache(cacheFile)
 	}
 
-	// Update last cache time
-	cfg.LastCacheTime = time.Now().Format(time.RFC3339)
+	if err := writeRecordCache(cacheFile, fetched); err != nil {
+		log.Error().Err(err).Str("source", src.Name()).Msg("Failed to cache source records")
+	}
+	cfg.SourceCacheTimes[key] = time.Now().Format(time.RFC3339)
+	cfg.SourceHTTPCache[key] = newMeta
 
-	// Save updated config
-	err := config.Save(cfg, config.DefaultConfigFileName)
+	return fetched, nil
+}
+
+// isFresh reports whether cacheTime (an RFC3339 timestamp, possibly empty)
+// is within sourceCacheMaxAge of now.
+func isFresh(cacheTime string) bool {
+	if cacheTime == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, cacheTime)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to save updated configuration")
+		return false
 	}
+	return time.Since(t) < sourceCacheMaxAge
 }
 
-// processDomainLists processes the cached domain lists and returns domains expiring on the reference date.
-// The reference date is determined by the local date (not UTC date) to ensure that domains expiring
-// "today" are correctly identified regardless of the user's time zone.
-func processDomainLists(cfg *domain.Config) []domain.DomainInfo {
-	var domains []domain.DomainInfo
-	// Use local time (not UTC) to ensure we get the correct reference date based on the user's local date.
-	// This is crucial for correct operation when the local date differs from the UTC date
-	// (e.g., at 00:38 CEST, which is 22:38 UTC of the previous day).
-	now := time.Now()
-	referenceDate := util.GetReferenceDate(now)
+func readRecordCache(path string) ([]source.DomainRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var records []source.DomainRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// writeRecordCache writes records to path, via a temp file plus os.Rename so
+// a crash or concurrent read mid-write never sees a partial cache file.
+func writeRecordCache(path string, records []source.DomainRecord) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
 
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// evaluateConcurrency caps how many domains are scored via
+// util.EvaluateDomain at once, so a source with millions of candidates
+// (a full zone dump) doesn't stall behind one scoring call at a time.
+const evaluateConcurrency = 8
+
+// processRecords filters records to those expiring on referenceDate and
+// returns them scored, deduplicated, and ordered. referenceDate comes from
+// parseReferenceDate, which defaults to the local date (not UTC date) so
+// domains expiring "today" are correctly identified regardless of the
+// user's time zone.
+func processRecords(cfg *domain.Config, records []source.DomainRecord, referenceDate time.Time) []domain.DomainInfo {
 	log.Info().
-		Time("local_time", now).
 		Time("reference_date", referenceDate).
 		Msg("Using reference date for domain filtering")
 
-	for _, filename := range cfg.CachedLists {
-		log.Info().Str("file", filename).Msg("Processing domain list")
+	// Filtering and de-dup stay sequential (the seen map isn't safe to share
+	// across goroutines); only the comparatively expensive EvaluateDomain
+	// call below is farmed out to a worker pool.
+	// seen dedupes domains across sources, which can list the same name with
+	// different case or a trailing dot. Names with \DDD escapes fail
+	// IsDomainName below and never reach the dedupe.
+	seen := make(map[string]bool)
+	var candidates []source.DomainRecord
+	for _, rec := range records {
+		if rec.ExpiryDate.Year() != referenceDate.Year() || rec.ExpiryDate.Month() != referenceDate.Month() || rec.ExpiryDate.Day() != referenceDate.Day() {
+			continue
+		}
 
-		// Read file
-		data, err := os.ReadFile(filename)
-		if err != nil {
-			log.Error().Err(err).Str("file", filename).Msg("Failed to read cached domain list")
+		if !util.IsDomainName(rec.Name) {
+			log.Debug().Str("domain", rec.Name).Msg("Skipping malformed domain name from source")
 			continue
 		}
 
-		// Process each line
-		lines := strings.Split(string(data), "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line == "" {
-				continue
-			}
+		canonical := util.CanonicalName(rec.Name)
+		if seen[canonical] {
+			log.Debug().Str("domain", rec.Name).Msg("Skipping duplicate domain already seen from another source")
+			continue
+		}
+		seen[canonical] = true
 
-			// Parse domain info
-			parts := strings.Fields(line)
-			if len(parts) < 2 {
-				continue
-			}
+		log.Debug().
+			Str("domain", rec.Name).
+			Time("expiry_date", rec.ExpiryDate).
+			Msg("Found domain expiring on reference date")
 
-			domainName := parts[0]
-			expiryDateStr := parts[1]
+		candidates = append(candidates, rec)
+	}
 
-			// Parse expiry date (format may vary, adjust as needed)
-			expiryDate, err := time.Parse("2006-01-02", expiryDateStr)
-			if err != nil {
-				log.Debug().Err(err).Str("domain", domainName).Str("date", expiryDateStr).Msg("Failed to parse expiry date")
-				continue
-			}
+	domains := evaluateCandidates(cfg, candidates)
 
-			// Add debug logging for specific domains of interest
-			if domainName == "d7.se" {
-				log.Info().
-					Str("domain", domainName).
-					Time("expiry_date", expiryDate).
-					Time("reference_date", referenceDate).
-					Bool("year_match", expiryDate.Year() == referenceDate.Year()).
-					Bool("month_match", expiryDate.Month() == referenceDate.Month()).
-					Bool("day_match", expiryDate.Day() == referenceDate.Day()).
-					Msg("Checking domain of interest")
-
-				// Calculate metrics for d7.se to debug its score
-				domainInfo := util.EvaluateDomain(domainName)
-
-				// Extract name part for pattern checking
-				domainNameOnly := domainName
-				if idx := strings.LastIndex(domainName, "."); idx != -1 {
-					domainNameOnly = domainName[:idx]
-				}
-
-				log.Info().
-					Str("domain", domainName).
-					Str("name_part", domainNameOnly).
-					Float64("length_score", domainInfo.LengthScore).
-					Float64("pronounceability", domainInfo.Pronounceable).
-					Float64("total_score", domainInfo.Score).
-					Int("length", domainInfo.Length).
-					Bool("is_letter_number", util.IsLetterNumberPattern(domainNameOnly)).
-					Msg("Score details for domain of interest")
-			}
+	// Canonical DNS order gives a deterministic secondary key, so ties in
+	// displayTopDomains' score sort (a stable sort) break the same way run to run.
+	sort.SliceStable(domains, func(i, j int) bool {
+		return util.CompareDomain(domains[i].Name, domains[j].Name) < 0
+	})
 
-			// Check if domain expires on the reference date
-			if expiryDate.Year() == referenceDate.Year() && expiryDate.Month() == referenceDate.Month() && expiryDate.Day() == referenceDate.Day() {
-				log.Debug().
-					Str("domain", domainName).
-					Time("expiry_date", expiryDate).
-					Msg("Found domain expiring on reference date")
-
-				// Calculate domain metrics
-				domainInfo := util.EvaluateDomain(domainName)
-				domainInfo.ExpiryDate = expiryDate
-				domains = append(domains, domainInfo)
+	return domains
+}
+
+// evaluateCandidates scores each candidate via util.EvaluateDomain, feeding
+// them through a channel to a fixed-size worker pool so scoring runs in
+// parallel. Result order is not preserved; callers must sort afterward.
+func evaluateCandidates(cfg *domain.Config, candidates []source.DomainRecord) []domain.DomainInfo {
+	jobs := make(chan source.DomainRecord)
+	results := make(chan domain.DomainInfo)
+
+	var workers sync.WaitGroup
+	for i := 0; i < evaluateConcurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for rec := range jobs {
+				info := util.EvaluateDomain(rec.Name, cfg.IDNA)
+				info.ExpiryDate = rec.ExpiryDate
+				results <- info
 			}
-		}
+		}()
 	}
 
+	go func() {
+		for _, rec := range candidates {
+			jobs <- rec
+		}
+		close(jobs)
+	}()
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	domains := make([]domain.DomainInfo, 0, len(candidates))
+	for info := range results {
+		domains = append(domains, info)
+	}
 	return domains
 }
 
 // displayTopDomains displays the top domains sorted by score
 func displayTopDomains(domains []domain.DomainInfo) {
 	// Sort domains by score
-	sort.Slice(domains, func(i, j int) bool {
+	sort.SliceStable(domains, func(i, j int) bool {
 		return domains[i].Score > domains[j].Score
 	})
 
@@ -224,20 +358,10 @@ func displayTopDomains(domains []domain.DomainInfo) {
 	fmt.Println("\nTop valuable domains expiring today:")
 	fmt.Println("======================================")
 
-	// Print header with explanation
-	//fmt.Println("Scoring factors:")
-	//fmt.Println("- Length: Shorter domains are better (2-3 chars are ideal)")
-	//fmt.Println("- Pattern: Letter-only domains (dv) > Letter+Number domains (d7) > Longer domains (dtv)")
-	//fmt.Println("- Dashes: Domains with dashes are penalized")
-	//fmt.Println("- TLD: Popular TLDs (.com, .net, .org) are preferred")
-	//fmt.Println("- Brand: Combination of pronounceability and memorability")
-	//fmt.Println("- Keyword: Domains containing valuable keywords get a bonus")
-	//fmt.Println()
-
 	// Print column headers
-	fmt.Printf("%-4s %-20s %-7s %-7s %-7s %-7s %-7s %-7s %s\n",
-		"Rank", "Domain", "Score", "Length", "TLD", "Brand", "Keyword", "Dash", "Type")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-4s %-20s %-20s %-7s %-7s %-7s %-7s %-7s %-7s %s\n",
+		"Rank", "Domain", "Unicode", "Score", "Length", "TLD", "Brand", "Keyword", "Dash", "Type")
+	fmt.Println(strings.Repeat("-", 100))
 
 	maxToShow := 100
 	if len(domains) < maxToShow {
@@ -273,10 +397,18 @@ func displayTopDomains(domains []domain.DomainInfo) {
 			keywordScore = fmt.Sprintf("%.2f", d.KeywordScore)
 		}
 
+		// Show the Unicode label only when it differs from Name, i.e. the
+		// domain is internationalized (Punycode on the wire, Unicode here).
+		unicodeLabel := "-"
+		if d.ULabel != "" && d.ULabel != d.Name {
+			unicodeLabel = d.ULabel
+		}
+
 		// Print d with all scoring components
-		fmt.Printf("%-4d %-20s %-7.2f %-7.2f %-7.2f %-7.2f %-7s %-7s %s\n",
+		fmt.Printf("%-4d %-20s %-20s %-7.2f %-7.2f %-7.2f %-7.2f %-7s %-7s %s\n",
 			i+1,
 			d.Name,
+			unicodeLabel,
 			d.Score,
 			d.LengthScore,
 			d.TLDScore,
@@ -3,15 +3,38 @@ package dropcatch
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/robfig/cron/v3"
 	"github.com/rs/zerolog/log"
+	"github.com/uberswe/LoopiaDomainBackorder/internal/available"
 	"github.com/uberswe/LoopiaDomainBackorder/pkg/api"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/clock"
 	"github.com/uberswe/LoopiaDomainBackorder/pkg/domain"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/fixture"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/history"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/journal"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/metrics"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/notify"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/registrar"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/shutdown"
 	"github.com/uberswe/LoopiaDomainBackorder/pkg/util"
 )
 
+// defaultHeartbeatInterval is used when Notifications.HeartbeatInterval is unset.
+const defaultHeartbeatInterval = util.TimeRecheckInterval
+
+// defaultConcurrency is how many domains are attempted in parallel when the
+// caller doesn't specify a concurrency override (e.g. via -concurrency 0).
+const defaultConcurrency = 4
+
 const (
 	fastRetryCount    = 3 // number of immediate retries after drop
 	fastRetryInterval = 100 * time.Millisecond
@@ -21,10 +44,149 @@ const (
 	preDroplead       = 100 * time.Millisecond
 )
 
-// AttemptDomainRegistration attempts to register a domain with retries
-func AttemptDomainRegistration(ctx context.Context, client *api.Client, domainName string, firstShot time.Time, resultCh chan<- domain.Result) {
+// tldDropHours flattens the config's per-TLD settings into the plain
+// map[string]int expected by util.NextDropForTLD.
+func tldDropHours(tlds map[string]domain.TLDConfig) map[string]int {
+	if len(tlds) == 0 {
+		return nil
+	}
+	hours := make(map[string]int, len(tlds))
+	for tld, cfg := range tlds {
+		hours[tld] = cfg.DropHourUTC
+	}
+	return hours
+}
+
+// primaryTLD returns the TLD of the first configured domain, used to look up
+// any per-TLD drop hour override. Returns "" if there are no domains.
+func primaryTLD(domains []string) string {
+	if len(domains) == 0 {
+		return ""
+	}
+	if idx := strings.LastIndex(domains[0], "."); idx != -1 {
+		return domains[0][idx+1:]
+	}
+	return ""
+}
+
+// failureReason maps an attempt error to a low-cardinality label for the
+// FailuresTotal metric.
+func failureReason(err error) string {
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized:
+			return "unauthorized"
+		case http.StatusTooManyRequests:
+			return "rate_limited"
+		}
+	}
+	return "other"
+}
+
+// fullJitterBackoff returns a random delay in [0, min(maxBackoff,
+// base*2^attempt)), spreading out concurrent domain workers instead of
+// retrying in lockstep ("full jitter":
+// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/).
+func fullJitterBackoff(base, maxBackoff time.Duration, attempt int) time.Duration {
+	upper := base
+	for i := 0; i < attempt && upper < maxBackoff; i++ {
+		upper *= 2
+		if upper <= 0 { // overflow
+			upper = maxBackoff
+			break
+		}
+	}
+	if upper > maxBackoff {
+		upper = maxBackoff
+	}
+	if upper <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(upper)))
+}
+
+// Option configures optional behaviour of Run and RunScheduled, such as
+// injecting a fake Clock for deterministic tests or a cancellable context
+// for graceful shutdown.
+type Option func(*options)
+
+type options struct {
+	clock       clock.Clock
+	baseCtx     context.Context
+	replayPath  string
+	replayMatch fixture.MatchStrategy
+	recordPath  string
+	closers     *shutdown.Registry
+}
+
+// WithClock overrides the Clock used for waiting, sleeping and ticking.
+// Defaults to clock.NewSystemClock().
+func WithClock(clk clock.Clock) Option {
+	return func(o *options) { o.clock = clk }
+}
+
+// WithContext overrides the base context Run/RunScheduled derive their
+// internal contexts from. Cancelling it (e.g. on SIGINT/SIGTERM) triggers a
+// graceful shutdown: in-flight attempts are given a chance to finish and, for
+// RunScheduled, the scheduler stops accepting new cron fires. Defaults to
+// context.Background().
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.baseCtx = ctx }
+}
+
+// WithReplay makes dry-run calls resolve against the recorded fixture
+// transcript at path, matched according to strategy, instead of always
+// returning "OK" (see pkg/fixture).
+func WithReplay(path string, strategy fixture.MatchStrategy) Option {
+	return func(o *options) { o.replayPath = path; o.replayMatch = strategy }
+}
+
+// WithRecord appends every real (non-dry) API call this run makes to a
+// fixture transcript at path, for later playback via WithReplay.
+func WithRecord(path string) Option {
+	return func(o *options) { o.recordPath = path }
+}
+
+// WithCloserRegistry registers the registrar.Registry created by Run or
+// RunScheduled (which in turn reaches the Loopia API client and any generic
+// backend) with registry, so the caller's bounded graceful shutdown closes
+// their idle connections alongside its other subsystems. Defaults to nil,
+// in which case nothing is registered.
+func WithCloserRegistry(registry *shutdown.Registry) Option {
+	return func(o *options) { o.closers = registry }
+}
+
+func buildOptions(opts []Option) *options {
+	o := &options{clock: clock.NewSystemClock(), baseCtx: context.Background(), replayMatch: fixture.MatchExact}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// clientOptions builds the api.ClientOptions common to both Run and
+// RunScheduled for replay/record, in addition to whatever base opts the
+// caller already assembled.
+func clientOptions(o *options, base []api.ClientOption) []api.ClientOption {
+	if o.replayPath != "" {
+		replay, err := fixture.LoadReplay(o.replayPath, o.replayMatch)
+		if err != nil {
+			log.Fatal().Err(err).Str("path", o.replayPath).Msg("Failed to load replay fixture")
+		}
+		base = append(base, api.WithReplay(replay))
+	}
+	if o.recordPath != "" {
+		base = append(base, api.WithRecorder(fixture.NewRecorder(o.recordPath)))
+	}
+	return base
+}
+
+// AttemptDomainRegistration attempts to register a domain with retries,
+// against whichever Registrar backend reg resolves to for that domain. j may
+// be nil, in which case no attempt journal is written.
+func AttemptDomainRegistration(ctx context.Context, reg registrar.Registrar, domainName string, firstShot time.Time, resultCh chan<- domain.Result, clk clock.Clock, j *journal.Journal) {
 	attemptNo := 0
-	backoff := time.Duration(0) // zero => fast retry window
 
 	for {
 		select {
@@ -38,7 +200,7 @@ func AttemptDomainRegistration(ctx context.Context, client *api.Client, domainNa
 		default:
 		}
 
-		start := time.Now()
+		start := clk.Now()
 		attemptNo++
 
 		log.Info().
@@ -47,20 +209,34 @@ func AttemptDomainRegistration(ctx context.Context, client *api.Client, domainNa
 			Time("start_time", start).
 			Msg("Starting domain registration attempt")
 
-		err := client.Attempt(domainName)
-		attemptDuration := time.Since(start)
+		err := registrar.Attempt(ctx, reg, domainName)
+		attemptDuration := clk.Now().Sub(start)
+
+		metrics.AttemptsTotal.Inc()
+		metrics.AttemptDuration.Observe(attemptDuration.Seconds())
+		metrics.AttemptSeconds.WithLabelValues(domainName).Observe(attemptDuration.Seconds())
+
+		if j != nil {
+			appendJournalEntry(j, domainName, attemptNo, attemptDuration, err, clk)
+		}
 
 		if err == nil {
 			log.Info().
 				Int("attempt", attemptNo).
 				Str("domain", domainName).
-				Dur("total_time", time.Since(firstShot)).
+				Dur("total_time", clk.Now().Sub(firstShot)).
 				Dur("attempt_duration", attemptDuration).
 				Msg("SUCCESS – domain registered")
+			metrics.SuccessesTotal.Inc()
+			metrics.AttemptTotal.WithLabelValues(domainName, "success").Inc()
+			metrics.TimeToFirstSuccess.Observe(clk.Now().Sub(firstShot).Seconds())
 			resultCh <- domain.Result{Domain: domainName, Success: true, Error: nil}
 			return
 		}
 
+		metrics.FailuresTotal.WithLabelValues(failureReason(err)).Inc()
+		metrics.AttemptTotal.WithLabelValues(domainName, "failure").Inc()
+
 		log.Warn().
 			Int("attempt", attemptNo).
 			Str("domain", domainName).
@@ -68,31 +244,123 @@ func AttemptDomainRegistration(ctx context.Context, client *api.Client, domainNa
 			Dur("attempt_duration", attemptDuration).
 			Msg("Attempt failed")
 
-		// Choose delay for next attempt
+		// Choose delay for next attempt. A server-provided Retry-After always
+		// wins; otherwise fall back to fully-jittered exponential backoff so
+		// concurrent domain workers don't retry against Loopia in lockstep.
 		var delay time.Duration
-		if attemptNo <= fastRetryCount {
+		var apiErr *api.APIError
+		switch {
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests && apiErr.RetryAfter > 0:
+			delay = apiErr.RetryAfter
+			log.Warn().
+				Str("domain", domainName).
+				Dur("retry_after", delay).
+				Msg("Honoring server Retry-After before next attempt")
+		case attemptNo <= fastRetryCount:
 			delay = fastRetryInterval
-		} else {
-			if backoff == 0 {
-				backoff = initialBackoff
-			} else {
-				backoff *= 2
-				if backoff > maxBackoff {
-					backoff = maxBackoff
-				}
-			}
-			delay = backoff
+		default:
+			delay = fullJitterBackoff(initialBackoff, maxBackoff, attemptNo-fastRetryCount)
 		}
 
 		// keep consistent cadence – deduct time spent inside the attempt
-		if sleep := delay - time.Since(start); sleep > 0 {
-			time.Sleep(sleep)
+		if sleep := delay - clk.Now().Sub(start); sleep > 0 {
+			select {
+			case <-ctx.Done():
+				log.Warn().
+					Str("domain", domainName).
+					Msg("Shutdown signal received while waiting to retry, aborting")
+				resultCh <- domain.Result{Domain: domainName, Success: false, Error: ctx.Err()}
+				return
+			case <-clk.After(sleep):
+			}
+		}
+	}
+}
+
+// appendJournalEntry records the outcome of a single attempt to j, logging a
+// warning rather than failing the attempt if the write itself fails.
+func appendJournalEntry(j *journal.Journal, domainName string, attemptNo int, duration time.Duration, err error, clk clock.Clock) {
+	entry := journal.Entry{
+		Timestamp: clk.Now(),
+		Domain:    domainName,
+		Attempt:   attemptNo,
+		Duration:  duration,
+		Success:   err == nil,
+	}
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		entry.Method = apiErr.Method
+		entry.StatusCode = apiErr.StatusCode
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if werr := j.Append(entry); werr != nil {
+		log.Warn().Err(werr).Str("domain", domainName).Msg("Failed to append attempt journal entry")
+	}
+}
+
+// loadJournalForResume opens the attempt journal at path (creating it if
+// needed), optionally resetting it first, and returns the entries recorded
+// within the last purchasingWindow so a resumed run can skip domains already
+// won and seed the rate limiter with calls already spent in that window.
+// The returned windowStart is the earliest of those entries' timestamps (or
+// now, if there are none), so the seeded counts stay in force until an hour
+// after the first resumed call.
+func loadJournalForResume(path string, reset bool, clk clock.Clock) (j *journal.Journal, recent []journal.Entry, windowStart time.Time) {
+	if reset {
+		if err := journal.Reset(path); err != nil {
+			log.Warn().Err(err).Str("path", path).Msg("Failed to reset attempt journal")
+		}
+	}
+
+	entries, err := journal.ReadAll(path)
+	if err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("Failed to read attempt journal, starting fresh")
+		entries = nil
+	}
+
+	windowStart = clk.Now().Add(-purchasingWindow)
+	recent = journal.Since(entries, windowStart)
+	windowStart = clk.Now()
+	for _, e := range recent {
+		if e.Timestamp.Before(windowStart) {
+			windowStart = e.Timestamp
 		}
 	}
+
+	j, err = journal.Open(path)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("Failed to open attempt journal")
+	}
+	return j, recent, windowStart
+}
+
+// journalPathOrDefault returns journalPath, falling back to
+// <cacheDir>/journal.jsonl when unset.
+func journalPathOrDefault(journalPath, cacheDir string) string {
+	if journalPath != "" {
+		return journalPath
+	}
+	if cacheDir == "" {
+		cacheDir = "cache"
+	}
+	return filepath.Join(cacheDir, journal.DefaultFileName)
 }
 
-// Run handles the dropcatch command functionality
-func Run(config *domain.Config, domainName string, dry bool, startNow bool, keepAwakeFlag bool) {
+// Run handles the dropcatch command functionality. concurrency bounds how
+// many domains are attempted in parallel; 0 falls back to defaultConcurrency.
+// journalPath is where the attempt journal is read from and appended to so a
+// crashed run can resume; "" falls back to <cache_dir>/journal.jsonl, and
+// resetJournal clears it before starting (discarding resume state).
+func Run(config *domain.Config, domainName string, dry bool, startNow bool, keepAwakeFlag bool, concurrency int, journalPath string, resetJournal bool, opts ...Option) {
+	o := buildOptions(opts)
+	clk := o.clock
+
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
 	// Check if we have any domains to register
 	if domainName != "" {
 		config.Domains = append(config.Domains, domainName)
@@ -102,26 +370,58 @@ func Run(config *domain.Config, domainName string, dry bool, startNow bool, keep
 		log.Fatal().Msg("No domains specified. Use -domain flag or add domains to config file")
 	}
 
+	j, recentEntries, windowStart := loadJournalForResume(journalPathOrDefault(journalPath, config.CacheDir), resetJournal, clk)
+	defer j.Close()
+
+	won := journal.WonDomains(recentEntries)
+	var pending []string
+	for _, d := range config.Domains {
+		if won[d] {
+			log.Info().Str("domain", d).Msg("Skipping domain already won per attempt journal (resumed)")
+			continue
+		}
+		pending = append(pending, d)
+	}
+	if len(pending) == 0 {
+		log.Info().Msg("All domains already won per attempt journal, nothing to do")
+		return
+	}
+	config.Domains = pending
+
 	// Create Loopia client
-	client, err := api.NewClient(config.Username, config.Password, dry)
+	client, err := api.NewClient(config.Username, config.Password, dry,
+		clientOptions(o, []api.ClientOption{
+			api.WithDomainPriorities(config.DomainPriorities),
+			api.WithInitialCallCounts(journal.CallCountsByDomain(recentEntries), windowStart),
+		})...,
+	)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create Loopia client")
 	}
+	reg := registrar.NewRegistry(config.Registrar, client)
+	if o.closers != nil {
+		o.closers.Register("registrar", reg)
+	}
+
+	notifier := notify.FromConfig(config.Notifications)
+	dropHours := tldDropHours(config.TLDs)
+	tld := primaryTLD(config.Domains)
 
 	// Calculate start time
-	now := time.Now()
-	drop := util.NextDrop(now)
+	now := clk.Now()
+	drop := util.NextDropForTLD(now, tld, dropHours)
 	firstShot := drop.Add(-preDroplead)
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), firstShot.Sub(now)+purchasingWindow)
+	// Create context with timeout, derived from the caller's base context so
+	// a signal-driven shutdown (see WithContext) cancels in-flight attempts too.
+	ctx, cancel := context.WithTimeout(o.baseCtx, firstShot.Sub(now)+purchasingWindow)
 	defer cancel()
 
 	if startNow {
 		// If -now flag is set, start immediately
-		firstShot = time.Now()
+		firstShot = clk.Now()
 		log.Info().Msg("Starting immediately due to -now flag")
-	} else if wait := time.Until(firstShot); wait > 0 {
+	} else if wait := firstShot.Sub(clk.Now()); wait > 0 {
 		log.Info().
 			Dur("wait_time", wait).
 			Str("first_attempt_time", firstShot.UTC().Format(time.RFC3339Nano)).
@@ -129,18 +429,23 @@ func Run(config *domain.Config, domainName string, dry bool, startNow bool, keep
 
 		// Start keep-awake routine if requested
 		if keepAwakeFlag {
-			go util.KeepAwake(ctx)
+			go util.KeepAwake(ctx, clk)
 		}
 
+		// Start the pre-drop heartbeat so users know the catcher is alive and waiting
+		go notify.StartHeartbeat(ctx, notifier, heartbeatInterval(config.Notifications))
+
 		// Wait with periodic time rechecking
 		for {
 			// Recalculate the current time and drop time
-			now = time.Now()
-			drop = util.NextDrop(now)
+			now = clk.Now()
+			drop = util.NextDropForTLD(now, tld, dropHours)
 			firstShot = drop.Add(-preDroplead)
 
 			// Calculate the new wait time
-			wait = time.Until(firstShot)
+			wait = firstShot.Sub(clk.Now())
+			metrics.SecondsUntilNextDrop.Set(wait.Seconds())
+			metrics.NextDropTimestamp.Set(float64(drop.Unix()))
 
 			// If it's time to start or less than a minute left, break the loop
 			if wait <= 0 || wait < util.TimeRecheckInterval {
@@ -159,36 +464,105 @@ func Run(config *domain.Config, domainName string, dry bool, startNow bool, keep
 				Str("updated_first_attempt_time", firstShot.UTC().Format(time.RFC3339Nano)).
 				Msg("Sleeping and will recheck time")
 
-			time.Sleep(sleepTime)
+			select {
+			case <-ctx.Done():
+				log.Info().Msg("Shutdown signal received while waiting for drop time, aborting")
+				return
+			case <-clk.After(sleepTime):
+			}
 		}
 
 		// Final sleep for any remaining time (less than a minute)
-		if wait := time.Until(firstShot); wait > 0 {
-			time.Sleep(wait)
+		if wait := firstShot.Sub(clk.Now()); wait > 0 {
+			select {
+			case <-ctx.Done():
+				log.Info().Msg("Shutdown signal received while waiting for drop time, aborting")
+				return
+			case <-clk.After(wait):
+			}
 		}
 	}
 
-	// Create slice to store results
-	var results []domain.Result
-	var resultsMutex sync.Mutex
+	// Process domains with a bounded worker pool, notifying and aggregating
+	// results as they stream back instead of waiting for every domain to finish.
+	results := make([]domain.Result, 0, len(config.Domains))
+	for result := range attemptAll(ctx, reg, config.Domains, firstShot, clk, concurrency, j) {
+		notifyResult(ctx, notifier, result)
+		results = append(results, result)
+	}
+	logSummary(results)
+}
+
+// heartbeatInterval returns the configured heartbeat interval, falling back
+// to defaultHeartbeatInterval when unset or invalid.
+func heartbeatInterval(cfg domain.Notifications) time.Duration {
+	if cfg.HeartbeatInterval == "" {
+		return defaultHeartbeatInterval
+	}
+	d, err := time.ParseDuration(cfg.HeartbeatInterval)
+	if err != nil {
+		log.Warn().Err(err).Str("heartbeat_interval", cfg.HeartbeatInterval).Msg("Invalid heartbeat interval, using default")
+		return defaultHeartbeatInterval
+	}
+	return d
+}
+
+// notifyResult sends a success/failure notification for a single result.
+func notifyResult(ctx context.Context, notifier notify.Notifier, result domain.Result) {
+	event := notify.Event{Domain: result.Domain, Timestamp: time.Now()}
+	if result.Success {
+		event.Type = notify.EventSuccess
+		event.Message = fmt.Sprintf("%s was registered successfully", result.Domain)
+	} else {
+		event.Type = notify.EventFailure
+		event.Message = fmt.Sprintf("%s registration failed: %v", result.Domain, result.Error)
+	}
+	if err := notifier.Notify(ctx, event); err != nil {
+		log.Warn().Err(err).Str("domain", result.Domain).Msg("Failed to send result notification")
+	}
+}
+
+// attemptAll runs AttemptDomainRegistration for every domain in domains,
+// resolving each one's backend via reg, with at most concurrency running at
+// once, and streams results back over the returned channel as they complete
+// so callers can notify and log as-you-go instead of waiting for every
+// domain to finish. The channel is closed once all domains have been attempted.
+func attemptAll(ctx context.Context, reg *registrar.Registry, domains []string, firstShot time.Time, clk clock.Clock, concurrency int, j *journal.Journal) <-chan domain.Result {
+	if concurrency <= 0 || concurrency > len(domains) {
+		concurrency = len(domains)
+	}
+
+	out := make(chan domain.Result, len(domains))
+	sem := make(chan struct{}, concurrency)
 	var wg sync.WaitGroup
 
-	// Process domains in parallel
-	log.Info().Int("domains", len(config.Domains)).Msg("Processing domains in parallel")
+	log.Info().
+		Int("domains", len(domains)).
+		Int("concurrency", concurrency).
+		Msg("Processing domains with a bounded worker pool")
 
-	for _, domainToRegister := range config.Domains {
+	for _, domainToRegister := range domains {
 		// Add to wait group before starting goroutine
 		wg.Add(1)
 
 		// Create a copy of domain for the goroutine
 		domainCopy := domainToRegister
 
-		// Start a goroutine for each domain
+		// Start a goroutine for each domain; sem bounds how many run at once
 		go func() {
 			defer wg.Done()
 
-			// Create a separate context for each domain to prevent cancellation affecting other domains
-			domainCtx, domainCancel := context.WithTimeout(context.Background(), purchasingWindow)
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				out <- domain.Result{Domain: domainCopy, Success: false, Error: ctx.Err()}
+				return
+			}
+			defer func() { <-sem }()
+
+			// Create a separate context for each domain to prevent cancellation affecting other domains,
+			// but still bounded by the caller's context so a graceful shutdown cuts attempts short.
+			domainCtx, domainCancel := context.WithTimeout(ctx, purchasingWindow)
 			defer domainCancel()
 
 			// Create a channel for this domain's result
@@ -196,16 +570,12 @@ func Run(config *domain.Config, domainName string, dry bool, startNow bool, keep
 
 			log.Info().Str("domain", domainCopy).Msg("Starting registration attempt for domain")
 
-			// Process this domain
-			AttemptDomainRegistration(domainCtx, client, domainCopy, firstShot, resultCh)
+			// Process this domain against whichever backend handles its TLD
+			AttemptDomainRegistration(domainCtx, reg.For(domainCopy), domainCopy, firstShot, resultCh, clk, j)
 
-			// Get the result
+			// Get the result and stream it back
 			result := <-resultCh
-
-			// Safely append to results slice
-			resultsMutex.Lock()
-			results = append(results, result)
-			resultsMutex.Unlock()
+			out <- result
 
 			log.Info().
 				Str("domain", domainCopy).
@@ -214,11 +584,17 @@ func Run(config *domain.Config, domainName string, dry bool, startNow bool, keep
 		}()
 	}
 
-	// Wait for all goroutines to complete
-	wg.Wait()
-	log.Info().Msg("All domain registration attempts completed")
+	go func() {
+		wg.Wait()
+		close(out)
+		log.Info().Msg("All domain registration attempts completed")
+	}()
+
+	return out
+}
 
-	// Process results
+// logSummary logs the aggregate success/failure counts for a batch of results.
+func logSummary(results []domain.Result) {
 	successCount := 0
 	failCount := 0
 
@@ -232,10 +608,149 @@ func Run(config *domain.Config, domainName string, dry bool, startNow bool, keep
 		}
 	}
 
-	// Log summary
 	log.Info().
-		Int("total", len(config.Domains)).
+		Int("total", len(results)).
 		Int("success", successCount).
 		Int("failed", failCount).
 		Msg("Domain registration summary")
 }
+
+// RunScheduled runs the dropcatch logic on a recurring cron schedule instead of
+// exiting after a single drop. On each fire it re-derives that fire's domain
+// list (config.Domains plus internal/available's per-date candidates for
+// today's reference date, see collectScheduledDomains), skips any domain
+// already won according to the persisted history, and attempts the rest
+// concurrently within a purchasingWindow timeout. Results are recorded to
+// the history file in the cache dir so restarts don't re-attempt won domains.
+// The attempt journal at journalPath (or "" for <cache_dir>/journal.jsonl) is
+// used the same way as in Run, to seed the rate limiter's call counts on
+// startup; resetJournal clears it first.
+func RunScheduled(config *domain.Config, schedule string, dry bool, concurrency int, journalPath string, resetJournal bool, opts ...Option) error {
+	o := buildOptions(opts)
+	clk := o.clock
+
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	if len(config.Domains) == 0 && len(config.Sources) == 0 {
+		log.Fatal().Msg("No domains specified and no sources configured. Add domains or sources to the config file")
+	}
+
+	cacheDir := config.CacheDir
+	if cacheDir == "" {
+		cacheDir = "cache"
+	}
+
+	j, recentEntries, windowStart := loadJournalForResume(journalPathOrDefault(journalPath, cacheDir), resetJournal, clk)
+	defer j.Close()
+
+	client, err := api.NewClient(config.Username, config.Password, dry,
+		clientOptions(o, []api.ClientOption{
+			api.WithDomainPriorities(config.DomainPriorities),
+			api.WithInitialCallCounts(journal.CallCountsByDomain(recentEntries), windowStart),
+		})...,
+	)
+	if err != nil {
+		return err
+	}
+	reg := registrar.NewRegistry(config.Registrar, client)
+	if o.closers != nil {
+		o.closers.Register("registrar", reg)
+	}
+
+	hist, err := history.Load(filepath.Join(cacheDir, history.DefaultFileName))
+	if err != nil {
+		return err
+	}
+
+	notifier := notify.FromConfig(config.Notifications)
+	dropHours := tldDropHours(config.TLDs)
+
+	c := cron.New()
+	_, err = c.AddFunc(schedule, func() {
+		domains := collectScheduledDomains(config)
+		runScheduledJob(o.baseCtx, reg, domains, hist, notifier, clk, dropHours, concurrency, j)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("schedule", schedule).Msg("Starting cron-based dropcatch scheduler")
+	c.Start()
+	<-o.baseCtx.Done()
+	log.Info().Msg("Shutdown signal received, stopping cron scheduler")
+	stopCtx := c.Stop()
+	<-stopCtx.Done()
+	log.Info().Msg("Cron scheduler stopped, all in-flight jobs completed")
+	return nil
+}
+
+// collectScheduledDomains returns one cron fire's full candidate domain
+// list: the operator's static config.Domains plus whatever
+// available.CandidateDomains derives as expiring on today's reference date
+// from the configured ingestion sources, deduplicated by canonical DNS name.
+// Re-deriving the per-date list on every fire, rather than only once at
+// startup, is what lets a long-running scheduled daemon chase each new day's
+// candidates instead of a list frozen at the moment it was started.
+func collectScheduledDomains(config *domain.Config) []string {
+	seen := make(map[string]bool)
+	domains := make([]string, 0, len(config.Domains))
+	for _, d := range config.Domains {
+		canonical := util.CanonicalName(d)
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		domains = append(domains, d)
+	}
+
+	candidates, err := available.CandidateDomains(config, "")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to derive per-date candidate domains; falling back to the static config.Domains list for this fire")
+		return domains
+	}
+	for _, c := range candidates {
+		canonical := util.CanonicalName(c.Name)
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		domains = append(domains, c.Name)
+	}
+	return domains
+}
+
+// runScheduledJob is invoked on every cron fire. It filters out already-won
+// domains, attempts the remaining ones straight away (the cron schedule sets
+// when that happens; firstShot, the next drop time, only serves as the
+// reference for timing logs and metrics) and persists results.
+func runScheduledJob(ctx context.Context, reg *registrar.Registry, domains []string, hist *history.History, notifier notify.Notifier, clk clock.Clock, dropHours map[string]int, concurrency int, j *journal.Journal) {
+	var pending []string
+	for _, d := range domains {
+		if hist.Won(d) {
+			log.Info().Str("domain", d).Msg("Skipping domain already won in a previous run")
+			continue
+		}
+		pending = append(pending, d)
+	}
+
+	if len(pending) == 0 {
+		log.Info().Msg("No pending domains for this scheduled run")
+		return
+	}
+
+	firstShot := util.NextDropForTLD(clk.Now(), primaryTLD(pending), dropHours).Add(-preDroplead)
+
+	results := make([]domain.Result, 0, len(pending))
+	for result := range attemptAll(ctx, reg, pending, firstShot, clk, concurrency, j) {
+		notifyResult(ctx, notifier, result)
+		results = append(results, result)
+		hist.Record(result.Domain, result.Success)
+	}
+	logSummary(results)
+
+	if err := hist.Save(); err != nil {
+		log.Error().Err(err).Msg("Failed to persist dropcatch history")
+	}
+}
@@ -2,20 +2,180 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/cookiejar"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kolo/xmlrpc"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/fixture"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
 const (
 	loopiaEndpoint = "https://api.loopia.se/RPCSERV"
 )
 
+// APIError is returned when an XML-RPC call fails with an HTTP-level error,
+// exposing the raw status code and any Retry-After value so callers can
+// branch on them instead of string-matching err.Error().
+type APIError struct {
+	StatusCode int           // HTTP status code, e.g. 401, 429; 0 if unknown
+	RetryAfter time.Duration // parsed Retry-After value; 0 if absent
+	Method     string        // XML-RPC method that was called
+	Err        error         // underlying error returned by the RPC transport
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("api: %s failed: %v", e.Method, e.Err)
+	}
+	return fmt.Sprintf("api: %s failed with status %d: %v", e.Method, e.StatusCode, e.Err)
+}
+
+func (e *APIError) Unwrap() error { return e.Err }
+
+// apiErrStatusLabel returns the loopia_api_calls_total status label for a
+// failed call: its HTTP status code as a string, or "error" if none was
+// captured (e.g. a connection failure before any response arrived).
+func apiErrStatusLabel(statusCode int) string {
+	if statusCode == 0 {
+		return "error"
+	}
+	return strconv.Itoa(statusCode)
+}
+
+// statusCapturingTransport wraps an http.RoundTripper and records the status
+// code and Retry-After header of the most recently completed response on c,
+// so Call can attach them to the APIError it returns. The underlying
+// kolo/xmlrpc client gives us no other way to see the raw HTTP response. It
+// also injects the current call's trace context as a W3C traceparent header,
+// since kolo/xmlrpc builds its *http.Request internally with no ctx of its
+// own to carry one.
+type statusCapturingTransport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+func (t *statusCapturingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.client.callsMutex.Lock()
+	traceCtx := t.client.pendingTraceCtx
+	t.client.callsMutex.Unlock()
+	if traceCtx != nil {
+		propagation.TraceContext{}.Inject(traceCtx, propagation.HeaderCarrier(req.Header))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if resp != nil {
+		t.client.callsMutex.Lock()
+		t.client.lastStatusCode = resp.StatusCode
+		t.client.lastRetryAfter = resp.Header.Get("Retry-After")
+		t.client.callsMutex.Unlock()
+	}
+	return resp, err
+}
+
+// parseRetryAfter parses a Retry-After header value, which per RFC 9110 is
+// either a number of seconds or an HTTP-date. Returns 0 if value is empty or
+// unparseable.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			secs = 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// ClientOption configures optional behaviour of NewClient.
+type ClientOption func(*Client)
+
+// WithDomainPriorities weights how the 60-calls/hour budget is split across
+// domains; a domain absent from priorities defaults to priority 1. See
+// domainLimiter for how shares are computed.
+func WithDomainPriorities(priorities map[string]int) ClientOption {
+	return func(c *Client) { c.domainPriorities = priorities }
+}
+
+// WithInitialCallCounts seeds the rate limiter's per-domain call counts and
+// window start, e.g. from a resumed attempt journal, so restarting the
+// process mid-window doesn't forget calls already spent in that window and
+// blow the hourly budget.
+func WithInitialCallCounts(counts map[string]int, windowStart time.Time) ClientOption {
+	return func(c *Client) {
+		c.initialCallCounts = counts
+		c.initialWindowStart = windowStart
+	}
+}
+
+// WithReplay makes dry-run calls (see NewClient's dry parameter) resolve
+// against a recorded fixture transcript instead of always returning "OK",
+// so contributors can reproduce an exact sequence of past API responses.
+func WithReplay(replay *fixture.Replay) ClientOption {
+	return func(c *Client) { c.replay = replay }
+}
+
+// WithRecorder appends every real (non-dry) call's method, params and
+// outcome to rec, so a live run's exact call sequence can be replayed later
+// via WithReplay.
+func WithRecorder(rec *fixture.Recorder) ClientOption {
+	return func(c *Client) { c.recorder = rec }
+}
+
+// WithTimeout bounds how long a single Call is allowed to take. It applies
+// only when the ctx passed to Call doesn't already carry an earlier
+// deadline, so a caller-supplied deadline always wins. Zero (the default)
+// leaves Call bounded only by whatever ctx the caller passes in.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) { c.timeout = d }
+}
+
+// WithRateLimit configures the default token-bucket RateLimiter's sustained
+// rate and burst, replacing the package default (60/hour, burst
+// defaultCallBurst). Ignored if WithRateLimiter is also given.
+func WithRateLimit(r rate.Limit, burst int) ClientOption {
+	return func(c *Client) { c.rateLimit, c.rateBurst = r, burst }
+}
+
+// WithRateLimiter overrides the client's RateLimiter entirely, e.g. with a
+// fake that always/never allows in tests. Takes precedence over
+// WithRateLimit.
+func WithRateLimiter(rl RateLimiter) ClientOption {
+	return func(c *Client) { c.rateLimiter = rl }
+}
+
+// WithRetryPolicy overrides the default RetryPolicy governing how Call
+// retries a transient failure (429, 5xx, network) with backoff, replacing
+// the package default (see DefaultRetryPolicy).
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) { c.retryPolicy = p }
+}
+
+// callsPerHour is Loopia's documented API rate limit.
+const callsPerHour = 60
+
 // Client wraps an xmlrpc.Client and automatically inserts
 // username + password as the first two parameters of every call.
 type Client struct {
@@ -24,118 +184,294 @@ type Client struct {
 	rpc      *xmlrpc.Client
 	dryRun   bool // if true, no RPC is executed (timing only)
 
-	// Rate limiting
-	callsMutex    sync.Mutex
-	callsThisHour int
-	hourStartTime time.Time
-	stopOn401     bool // if true, stop sending requests on 401 Unauthorized errors
-	stopOn429     bool // if true, stop sending requests on 429 Too Many Requests errors
+	// Rate limiting. limiter tracks each domain's share of the budget and
+	// rejects a call once that domain's quota for the window is used up (it
+	// also feeds the loopia_rate_limit_remaining metric and journal resume);
+	// rateLimiter is the pluggable pacer that governs how fast calls may go
+	// out overall, a token bucket that queues instead of rejecting.
+	callsMutex         sync.Mutex
+	limiter            *domainLimiter
+	domainPriorities   map[string]int // set via WithDomainPriorities, consumed when building limiter
+	initialCallCounts  map[string]int // set via WithInitialCallCounts, consumed when building limiter
+	initialWindowStart time.Time
+	rateLimiter        RateLimiter
+	rateLimit          rate.Limit  // set via WithRateLimit; 0 means use the package default
+	rateBurst          int         // set via WithRateLimit; 0 means use defaultCallBurst
+	retryPolicy        RetryPolicy // set via WithRetryPolicy; zero value means use DefaultRetryPolicy
+
+	// lastStatusCode and lastRetryAfter are set by statusCapturingTransport
+	// after every HTTP round trip, guarded by callsMutex.
+	lastStatusCode int
+	lastRetryAfter string
+
+	// tracer creates the per-call span Call starts for every XML-RPC method,
+	// set via WithTracerProvider or otherwise defaulted to the global
+	// TracerProvider. pendingTraceCtx is the span context of whichever call
+	// is currently in flight, read by statusCapturingTransport to inject a
+	// traceparent header; like lastStatusCode it's a single shared field
+	// guarded by callsMutex, so it's only meaningful for one call at a time.
+	tracer          trace.Tracer
+	pendingTraceCtx context.Context
+
+	// replay and recorder back -replay/-record (see pkg/fixture); either or
+	// both may be nil.
+	replay   *fixture.Replay
+	recorder *fixture.Recorder
+
+	// timeout is the default per-call deadline set via WithTimeout; 0 means
+	// Call relies solely on the ctx its caller passes in.
+	timeout time.Duration
+
+	// httpClient is kept only so Close can release its idle connections on
+	// shutdown; all actual RPCs go through rpc, which was built from its
+	// Transport.
+	httpClient *http.Client
 }
 
+// callSeq assigns each Call invocation a monotonically increasing id so log
+// lines belonging to the same call (request and response) can be
+// correlated, including across the goroutine boundary Call uses to make
+// the synchronous RPC cancellable.
+var callSeq uint64
+
 // NewClient creates a new Loopia API client
-func NewClient(username, password string, dry bool) (*Client, error) {
+func NewClient(username, password string, dry bool, opts ...ClientOption) (*Client, error) {
 	jar, _ := cookiejar.New(nil)
 	httpClient := &http.Client{Jar: jar, Timeout: 15 * time.Second}
 
-	c, err := xmlrpc.NewClient(loopiaEndpoint, httpClient.Transport)
+	c := &Client{
+		username: username,
+		password: password,
+		dryRun:   dry,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.limiter = newDomainLimiter(callsPerHour, time.Hour, c.domainPriorities)
+	if c.initialCallCounts != nil {
+		c.limiter.seed(c.initialCallCounts, c.initialWindowStart)
+	}
+
+	if c.rateLimiter == nil {
+		r := c.rateLimit
+		if r == 0 {
+			r = perHour(callsPerHour)
+		}
+		burst := c.rateBurst
+		if burst == 0 {
+			burst = defaultCallBurst
+		}
+		c.rateLimiter = newTokenBucketLimiter(r, burst)
+	}
+
+	if c.retryPolicy.MaxAttempts == 0 {
+		c.retryPolicy = DefaultRetryPolicy()
+	}
+
+	if c.tracer == nil {
+		c.tracer = otel.Tracer(tracerName)
+	}
+
+	httpClient.Transport = &statusCapturingTransport{base: http.DefaultTransport, client: c}
+
+	rpc, err := xmlrpc.NewClient(loopiaEndpoint, httpClient.Transport)
 	if err != nil {
 		return nil, err
 	}
-	return &Client{
-		username:      username,
-		password:      password,
-		rpc:           c,
-		dryRun:        dry,
-		callsThisHour: 0,
-		hourStartTime: time.Now(),
-		stopOn401:     false,
-		stopOn429:     false,
-	}, nil
+	c.rpc = rpc
+	c.httpClient = httpClient
+	return c, nil
 }
 
-// Call invokes an XML‑RPC method with authentication prepended.
-func (c *Client) Call(method string, params ...interface{}) (interface{}, error) {
+// Close releases c's idle HTTP connections, satisfying shutdown.Closer so a
+// bounded graceful shutdown can clean it up alongside the other subsystems
+// it's registered with. ctx is unused: closing idle connections is
+// synchronous and doesn't block.
+func (c *Client) Close(ctx context.Context) error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// Call invokes an XML‑RPC method with authentication prepended. domainName
+// identifies which domain this call counts against for rate limiting; pass
+// "" for calls that aren't tied to a single domain. ctx bounds the call:
+// if it's cancelled or its deadline (or the client's WithTimeout default)
+// elapses before Loopia responds, Call returns ctx.Err() without waiting
+// for the RPC goroutine, though kolo/xmlrpc gives us no way to abort the
+// underlying HTTP round trip itself, so that goroutine keeps running in
+// the background until the transport's own timeout trips.
+func (c *Client) Call(ctx context.Context, domainName, method string, params ...interface{}) (interface{}, error) {
+	if c.timeout > 0 {
+		if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+		}
+	}
+
+	ctx, span := c.tracer.Start(ctx, method, trace.WithAttributes(
+		attribute.String("domain", domainName),
+	))
+	defer span.End()
+
 	all := append([]interface{}{c.username, c.password}, params...)
 
+	callID := atomic.AddUint64(&callSeq, 1)
+
 	// Create a logger event for this specific call
 	reqLogger := log.With().
+		Uint64("call_id", callID).
 		Str("method", method).
+		Str("domain", domainName).
 		Str("operation", "api_call").
 		Time("request_time", time.Now()).
 		Logger()
 
+	if err := ctx.Err(); err != nil {
+		reqLogger.Error().Err(err).Msg("Call aborted before it started")
+		return nil, err
+	}
+
 	if c.dryRun {
+		if c.replay != nil {
+			resp, err := c.replay.Next(method, params)
+			if err != nil {
+				reqLogger.Error().Err(err).Msg("[DRY-RUN] replay fixture lookup failed")
+				return nil, err
+			}
+			reqLogger.Info().
+				Interface("params", params).
+				Interface("response", resp).
+				Msg("[DRY-RUN] API call replayed from fixture")
+			return resp, nil
+		}
 		reqLogger.Info().
 			Interface("params", params).
 			Msg("[DRY-RUN] API call simulated")
 		return "OK", nil
 	}
 
-	// Rate limiting check
-	c.callsMutex.Lock()
-
-	// Check if we need to reset the hour counter
-	now := time.Now()
-	if now.Sub(c.hourStartTime) >= time.Hour {
-		reqLogger.Info().
-			Int("previous_hour_calls", c.callsThisHour).
-			Time("new_hour_start", now).
-			Msg("Resetting API call counter for new hour")
-		c.callsThisHour = 0
-		c.hourStartTime = now
+	// Retry loop: each pass paces itself against the RateLimiter, checks
+	// domain fairness, and makes one real attempt. A transient error (429,
+	// 5xx, network) is retried with backoff up to policy.MaxAttempts; a
+	// terminal one (401, an "unavailable" XML-RPC fault) is returned
+	// immediately. This replaces the old permanent stopOn429/stopOn401
+	// latch, which disabled the whole client forever on a single 429 even
+	// though a later attempt (e.g. the real registration, seconds after a
+	// 429 during pre-drop polling) might well succeed.
+	maxAttempts := c.retryPolicy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
 	}
 
-	// Check if we've reached the limit
-	if c.callsThisHour >= 60 {
-		c.callsMutex.Unlock()
-		errMsg := "API call limit of 60 calls per hour reached"
-		reqLogger.Error().
-			Int("calls_this_hour", c.callsThisHour).
-			Time("hour_start", c.hourStartTime).
-			Time("hour_end", c.hourStartTime.Add(time.Hour)).
-			Msg(errMsg)
-		return nil, errors.New(errMsg)
-	}
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		attemptLogger := reqLogger.With().Int("attempt", attempt).Logger()
 
-	// Check if we should stop due to previous error
-	if c.stopOn401 || c.stopOn429 {
-		// We'll check these flags but still allow the call to proceed
-		// This way the application can decide what to do with the error
-		errorType := ""
-		if c.stopOn401 {
-			errorType = "401 Unauthorized"
+		reply, err := c.callOnce(ctx, domainName, method, all, params, attemptLogger)
+		if err == nil {
+			span.SetStatus(codes.Ok, "")
+			return reply, nil
 		}
-		if c.stopOn429 {
-			if errorType != "" {
-				errorType += " or "
-			}
-			errorType += "429 Too Many Requests"
+		lastErr = err
+
+		if attempt == maxAttempts || !c.retryPolicy.ShouldRetry(err) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+
+		delay := c.retryPolicy.nextDelay(attempt, err)
+		attemptLogger.Warn().Err(err).Dur("retry_delay", delay).Msg("Retrying API call after transient error")
+		select {
+		case <-ctx.Done():
+			span.RecordError(ctx.Err())
+			span.SetStatus(codes.Error, ctx.Err().Error())
+			return nil, ctx.Err()
+		case <-time.After(delay):
 		}
-		reqLogger.Warn().
-			Str("error_type", errorType).
-			Msg("Making API call despite previous error")
 	}
+	span.RecordError(lastErr)
+	span.SetStatus(codes.Error, lastErr.Error())
+	return nil, lastErr
+}
 
-	// Increment the counter
-	c.callsThisHour++
-	callNumber := c.callsThisHour
+// callOnce makes a single XML-RPC attempt: it checks the rate limiter and
+// per-domain fairness budget, invokes the RPC on its own goroutine so ctx
+// cancellation can return promptly, and classifies any failure into an
+// *APIError.
+func (c *Client) callOnce(ctx context.Context, domainName, method string, all, params []interface{}, reqLogger zerolog.Logger) (interface{}, error) {
+	// Pace the call against the pluggable RateLimiter. Unlike the old hard
+	// per-window wall, Wait queues the caller until a token is free (or ctx
+	// gives up), so a handful of rapid retries at the drop moment can burst
+	// through instead of failing outright at exactly the 61st call.
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		reqLogger.Error().Err(err).Msg("Rate limiter wait aborted")
+		return nil, err
+	}
+
+	// Fairness check – the 60-calls/hour budget is additionally split
+	// across domains (weighted by priority) so one hot domain being
+	// retried aggressively can't starve the others' share of it.
+	c.callsMutex.Lock()
+	allowed, quota := c.limiter.allow(domainName)
+	if !allowed {
+		c.callsMutex.Unlock()
+		err := rateLimitError(domainName, quota, c.limiter.window)
+		reqLogger.Error().Int("quota", quota).Msg(err.Error())
+		return nil, err
+	}
 	c.callsMutex.Unlock()
 
+	metrics.RateLimitRemaining.WithLabelValues(domainName).Set(float64(c.limiter.remaining(domainName)))
+	metrics.APICallsRemainingThisHour.Set(float64(c.limiter.totalRemaining()))
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int("rate_limit_quota", quota))
+
 	// Log the request details
 	reqLogger.Info().
 		Interface("params", params).
-		Int("calls_this_hour", callNumber).
+		Int("quota", quota).
 		Msg("Sending API request")
 
 	// Record the start time for precise timing
 	start := time.Now()
 
-	// Make the actual API call
+	// Make the actual API call. It runs on its own goroutine so a cancelled
+	// or expired ctx can make Call return promptly instead of blocking
+	// until kolo/xmlrpc's own HTTP client timeout trips.
+	c.callsMutex.Lock()
+	c.pendingTraceCtx = ctx
+	c.lastStatusCode, c.lastRetryAfter = 0, ""
+	c.callsMutex.Unlock()
+
 	var reply interface{}
-	err := c.rpc.Call(method, all, &reply)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.rpc.Call(method, all, &reply)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		duration := time.Since(start)
+		reqLogger.Error().Err(ctx.Err()).Dur("duration_ms", duration).Msg("Call cancelled while waiting for response")
+		return nil, ctx.Err()
+	}
 
 	// Calculate the duration
 	duration := time.Since(start)
+	metrics.APICallDuration.WithLabelValues(method).Observe(duration.Seconds())
+	span.SetAttributes(attribute.Int64("duration_ms", duration.Milliseconds()))
+
+	if c.recorder != nil {
+		if rerr := c.recorder.Record(method, params, reply, err, duration); rerr != nil {
+			log.Warn().Err(rerr).Str("method", method).Msg("Failed to record API call to fixture")
+		}
+	}
 
 	// Log the response with timing information
 	respLogger := reqLogger.With().
@@ -148,27 +484,46 @@ func (c *Client) Call(method string, params ...interface{}) (interface{}, error)
 			Err(err).
 			Msg("API call failed")
 
-		// Check for specific error codes
-		errStr := err.Error()
-		if errStr == "401 Unauthorized" || errStr == "429 Too Many Requests" {
-			c.callsMutex.Lock()
-			if errStr == "401 Unauthorized" {
-				c.stopOn401 = true
-				respLogger.Error().
-					Str("error_code", errStr).
-					Msg("Received 401 Unauthorized error, stopping further API calls")
-			} else if errStr == "429 Too Many Requests" {
-				c.stopOn429 = true
-				respLogger.Error().
-					Str("error_code", errStr).
-					Msg("Received 429 Too Many Requests error, stopping further API calls")
-			}
-			c.callsMutex.Unlock()
+		c.callsMutex.Lock()
+		statusCode, retryAfter := c.lastStatusCode, c.lastRetryAfter
+		c.callsMutex.Unlock()
+
+		// Loopia reports most errors (AUTH_ERROR, RATE_LIMITED, ...) as an
+		// XML-RPC fault in an otherwise-200 response body, not as an HTTP
+		// status. Wrap those into a LoopiaFault so callers can branch on
+		// them with errors.Is/As instead of string-matching err.Error().
+		var fault xmlrpc.FaultError
+		if errors.As(err, &fault) {
+			err = &LoopiaFault{Code: fault.Code, Message: fault.String}
 		}
 
-		return nil, err
+		apiErr := &APIError{
+			StatusCode: statusCode,
+			RetryAfter: parseRetryAfter(retryAfter),
+			Method:     method,
+			Err:        err,
+		}
+
+		metrics.APICallsTotal.WithLabelValues(method, apiErrStatusLabel(apiErr.StatusCode)).Inc()
+		span.SetAttributes(attribute.Int("http_status", apiErr.StatusCode))
+
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized:
+			respLogger.Error().
+				Int("status_code", apiErr.StatusCode).
+				Msg("Received 401 Unauthorized error")
+		case http.StatusTooManyRequests:
+			respLogger.Error().
+				Int("status_code", apiErr.StatusCode).
+				Dur("retry_after", apiErr.RetryAfter).
+				Msg("Received 429 Too Many Requests error")
+		}
+
+		return nil, apiErr
 	}
 
+	metrics.APICallsTotal.WithLabelValues(method, "ok").Inc()
+
 	respLogger.Info().
 		Interface("response", reply).
 		Msg("API call successful")
@@ -177,7 +532,7 @@ func (c *Client) Call(method string, params ...interface{}) (interface{}, error)
 }
 
 // OrderDomain attempts to order a domain
-func (c *Client) OrderDomain(domain string) error {
+func (c *Client) OrderDomain(ctx context.Context, domain string) error {
 	// Log the domain order attempt
 	log.Info().
 		Str("domain", domain).
@@ -186,7 +541,7 @@ func (c *Client) OrderDomain(domain string) error {
 		Msg("Attempting to order domain")
 
 	// orderDomain(..., domain, true) – true == pay with credits automatically
-	_, err := c.Call("orderDomain", domain, true)
+	_, err := c.Call(ctx, domain, "orderDomain", domain, true)
 
 	if err != nil {
 		log.Error().
@@ -195,33 +550,36 @@ func (c *Client) OrderDomain(domain string) error {
 			Str("operation", "order_domain").
 			Time("failure_time", time.Now()).
 			Msg("Domain order failed")
+		metrics.DomainOrderAttemptsTotal.WithLabelValues("failure").Inc()
 	} else {
 		log.Info().
 			Str("domain", domain).
 			Str("operation", "order_domain").
 			Time("success_time", time.Now()).
 			Msg("Domain order successful")
+		metrics.DomainOrderAttemptsTotal.WithLabelValues("success").Inc()
 	}
 
 	return err
 }
 
-// PayInvoiceIfAny checks if there's an invoice for the domain and pays it
-func (c *Client) PayInvoiceIfAny(domain string) error {
+// InvoiceReference returns the reference number of domain's outstanding
+// invoice, or "" if it has none.
+func (c *Client) InvoiceReference(ctx context.Context, domain string) (string, error) {
 	log.Info().
 		Str("domain", domain).
 		Str("operation", "check_invoice").
 		Time("check_time", time.Now()).
 		Msg("Checking for invoice to pay")
 
-	resp, err := c.Call("getDomain", domain)
+	resp, err := c.Call(ctx, domain, "getDomain", domain)
 	if err != nil {
 		log.Error().
 			Err(err).
 			Str("domain", domain).
 			Str("operation", "check_invoice").
 			Msg("Failed to get domain information")
-		return err
+		return "", err
 	}
 
 	m, ok := resp.(map[string]interface{})
@@ -231,10 +589,20 @@ func (c *Client) PayInvoiceIfAny(domain string) error {
 			Str("operation", "check_invoice").
 			Interface("response", resp).
 			Msg("Unexpected response format from getDomain")
-		return errors.New("unexpected response format from getDomain")
+		return "", errors.New("unexpected response format from getDomain")
 	}
 
 	ref, _ := m["reference_no"].(string)
+	return ref, nil
+}
+
+// PayInvoiceIfAny checks if there's an invoice for the domain and pays it
+func (c *Client) PayInvoiceIfAny(ctx context.Context, domain string) error {
+	ref, err := c.InvoiceReference(ctx, domain)
+	if err != nil {
+		return err
+	}
+
 	if ref == "" {
 		log.Info().
 			Str("domain", domain).
@@ -250,7 +618,7 @@ func (c *Client) PayInvoiceIfAny(domain string) error {
 		Time("payment_attempt_time", time.Now()).
 		Msg("Attempting to pay invoice")
 
-	_, err = c.Call("payInvoiceUsingCredits", ref)
+	_, err = c.Call(ctx, domain, "payInvoiceUsingCredits", ref)
 
 	if err != nil {
 		log.Error().
@@ -260,6 +628,7 @@ func (c *Client) PayInvoiceIfAny(domain string) error {
 			Str("operation", "pay_invoice").
 			Time("failure_time", time.Now()).
 			Msg("Invoice payment failed")
+		metrics.InvoicePaymentsTotal.WithLabelValues("failure").Inc()
 	} else {
 		log.Info().
 			Str("domain", domain).
@@ -267,78 +636,8 @@ func (c *Client) PayInvoiceIfAny(domain string) error {
 			Str("operation", "pay_invoice").
 			Time("success_time", time.Now()).
 			Msg("Invoice payment successful")
+		metrics.InvoicePaymentsTotal.WithLabelValues("success").Inc()
 	}
 
 	return err
 }
-
-// Attempt tries to register and immediately pay for the domain.
-func (c *Client) Attempt(domain string) error {
-	attemptStart := time.Now()
-
-	// Check if we should stop due to previous 401 or 429 error
-	c.callsMutex.Lock()
-	var errMsg string
-	if c.stopOn401 && c.stopOn429 {
-		errMsg = "Aborting attempt due to previous 401 Unauthorized and 429 Too Many Requests errors"
-	} else if c.stopOn401 {
-		errMsg = "Aborting attempt due to previous 401 Unauthorized error"
-	} else if c.stopOn429 {
-		errMsg = "Aborting attempt due to previous 429 Too Many Requests error"
-	}
-
-	if errMsg != "" {
-		c.callsMutex.Unlock()
-		log.Error().
-			Str("domain", domain).
-			Str("operation", "registration_attempt").
-			Bool("stopOn401", c.stopOn401).
-			Bool("stopOn429", c.stopOn429).
-			Msg(errMsg)
-		return errors.New(errMsg)
-	}
-	c.callsMutex.Unlock()
-
-	log.Info().
-		Str("domain", domain).
-		Str("operation", "registration_attempt").
-		Time("start_time", attemptStart).
-		Msg("Starting complete domain registration attempt")
-
-	// Try to order the domain
-	if err := c.OrderDomain(domain); err != nil {
-		log.Error().
-			Err(err).
-			Str("domain", domain).
-			Str("operation", "registration_attempt").
-			Dur("duration", time.Since(attemptStart)).
-			Time("end_time", time.Now()).
-			Msg("Domain registration attempt failed at order step")
-		return err
-	}
-
-	// Try to pay for the domain if needed
-	err := c.PayInvoiceIfAny(domain)
-	attemptEnd := time.Now()
-	attemptDuration := attemptEnd.Sub(attemptStart)
-
-	if err != nil {
-		log.Error().
-			Err(err).
-			Str("domain", domain).
-			Str("operation", "registration_attempt").
-			Dur("duration", attemptDuration).
-			Time("end_time", attemptEnd).
-			Msg("Domain registration attempt failed at payment step")
-		return err
-	}
-
-	log.Info().
-		Str("domain", domain).
-		Str("operation", "registration_attempt").
-		Dur("duration", attemptDuration).
-		Time("end_time", attemptEnd).
-		Msg("Complete domain registration attempt successful")
-
-	return nil
-}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ZoneRecord models a single DNS resource record as returned or accepted by
+// Loopia's zone record XML-RPC calls (getZoneRecords, addZoneRecord,
+// updateZoneRecord). ID is assigned by Loopia and is required by
+// UpdateZoneRecord/RemoveZoneRecord but ignored by AddZoneRecord.
+type ZoneRecord struct {
+	ID       int
+	Type     string // "A", "AAAA", "CNAME", "MX", "TXT", "NS", etc.
+	Priority int    // used by MX and SRV records; 0 otherwise
+	TTL      int
+	Rdata    string
+}
+
+// toMap converts r to the struct shape Loopia's XML-RPC API expects as a
+// zone record parameter.
+func (r ZoneRecord) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"record_id": r.ID,
+		"type":      r.Type,
+		"priority":  r.Priority,
+		"ttl":       r.TTL,
+		"rdata":     r.Rdata,
+	}
+}
+
+// zoneRecordFromMap decodes a single zone record struct from an XML-RPC
+// response, tolerating the int/int64/float64 variance different XML-RPC
+// transports use for <i4> values.
+func zoneRecordFromMap(m map[string]interface{}) ZoneRecord {
+	return ZoneRecord{
+		ID:       asInt(m["record_id"]),
+		Type:     asString(m["type"]),
+		Priority: asInt(m["priority"]),
+		TTL:      asInt(m["ttl"]),
+		Rdata:    asString(m["rdata"]),
+	}
+}
+
+// asInt extracts an int from an XML-RPC reply value, which kolo/xmlrpc may
+// hand back as int, int64, or float64 depending on the declared XML-RPC type.
+func asInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
+
+// asString extracts a string from an XML-RPC reply value, returning "" if v
+// isn't a string.
+func asString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// GetDomains lists every domain on the account.
+func (c *Client) GetDomains(ctx context.Context) ([]string, error) {
+	resp, err := c.Call(ctx, "", "getDomains")
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := resp.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("api: unexpected response format from getDomains")
+	}
+
+	domains := make([]string, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name := asString(m["domain"]); name != "" {
+			domains = append(domains, name)
+		}
+	}
+	return domains, nil
+}
+
+// GetSubdomains lists every subdomain configured for domainName, as Loopia
+// returns them (the bare apex appears as "@" if Loopia lists it).
+func (c *Client) GetSubdomains(ctx context.Context, domainName string) ([]string, error) {
+	resp, err := c.Call(ctx, domainName, "getSubdomains", domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := resp.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("api: unexpected response format from getSubdomains")
+	}
+
+	subdomains := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			subdomains = append(subdomains, s)
+		}
+	}
+	return subdomains, nil
+}
+
+// AddSubdomain creates subdomain under domainName.
+func (c *Client) AddSubdomain(ctx context.Context, domainName, subdomain string) error {
+	_, err := c.Call(ctx, domainName, "addSubdomain", domainName, subdomain)
+	if err != nil {
+		log.Error().Err(err).Str("domain", domainName).Str("subdomain", subdomain).Msg("Failed to add subdomain")
+	}
+	return err
+}
+
+// RemoveSubdomain deletes subdomain (and every zone record under it) from
+// domainName.
+func (c *Client) RemoveSubdomain(ctx context.Context, domainName, subdomain string) error {
+	_, err := c.Call(ctx, domainName, "removeSubdomain", domainName, subdomain)
+	if err != nil {
+		log.Error().Err(err).Str("domain", domainName).Str("subdomain", subdomain).Msg("Failed to remove subdomain")
+	}
+	return err
+}
+
+// GetZoneRecords lists every DNS record configured for subdomain under
+// domainName. Pass "@" for the apex.
+func (c *Client) GetZoneRecords(ctx context.Context, domainName, subdomain string) ([]ZoneRecord, error) {
+	resp, err := c.Call(ctx, domainName, "getZoneRecords", domainName, subdomain)
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := resp.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("api: unexpected response format from getZoneRecords")
+	}
+
+	records := make([]ZoneRecord, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		records = append(records, zoneRecordFromMap(m))
+	}
+	return records, nil
+}
+
+// AddZoneRecord creates a new DNS record under subdomain (record.ID is
+// ignored; Loopia assigns one).
+func (c *Client) AddZoneRecord(ctx context.Context, domainName, subdomain string, record ZoneRecord) error {
+	_, err := c.Call(ctx, domainName, "addZoneRecord", domainName, subdomain, record.toMap())
+	if err != nil {
+		log.Error().Err(err).Str("domain", domainName).Str("subdomain", subdomain).Str("type", record.Type).Msg("Failed to add zone record")
+	}
+	return err
+}
+
+// UpdateZoneRecord replaces the existing record identified by record.ID
+// under subdomain.
+func (c *Client) UpdateZoneRecord(ctx context.Context, domainName, subdomain string, record ZoneRecord) error {
+	_, err := c.Call(ctx, domainName, "updateZoneRecord", domainName, subdomain, record.toMap())
+	if err != nil {
+		log.Error().Err(err).Str("domain", domainName).Str("subdomain", subdomain).Int("record_id", record.ID).Msg("Failed to update zone record")
+	}
+	return err
+}
+
+// RemoveZoneRecord deletes the record identified by recordID under
+// subdomain.
+func (c *Client) RemoveZoneRecord(ctx context.Context, domainName, subdomain string, recordID int) error {
+	_, err := c.Call(ctx, domainName, "removeZoneRecord", domainName, subdomain, recordID)
+	if err != nil {
+		log.Error().Err(err).Str("domain", domainName).Str("subdomain", subdomain).Int("record_id", recordID).Msg("Failed to remove zone record")
+	}
+	return err
+}
+
+// UpdateDNSServers points domainName at nameServers, replacing whatever is
+// currently configured.
+func (c *Client) UpdateDNSServers(ctx context.Context, domainName string, nameServers []string) error {
+	_, err := c.Call(ctx, domainName, "updateNameServers", domainName, nameServers)
+	if err != nil {
+		log.Error().Err(err).Str("domain", domainName).Strs("name_servers", nameServers).Msg("Failed to update name servers")
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors a LoopiaFault classifies as, so callers can branch with
+// errors.Is(err, api.ErrUnauthorized) instead of string-matching the fault
+// message themselves.
+var (
+	ErrUnauthorized        = fmt.Errorf("loopia: unauthorized")
+	ErrRateLimited         = fmt.Errorf("loopia: rate limited")
+	ErrDomainUnavailable   = fmt.Errorf("loopia: domain unavailable")
+	ErrInsufficientCredits = fmt.Errorf("loopia: insufficient credits")
+	ErrInvoiceNotFound     = fmt.Errorf("loopia: invoice not found")
+)
+
+// LoopiaFault represents an XML-RPC fault Loopia returns in the response
+// body itself rather than as an HTTP status code, e.g. "AUTH_ERROR",
+// "RATE_LIMITED", "UNKNOWN_ERROR". Code and Message are taken directly from
+// the underlying xmlrpc.FaultError.
+type LoopiaFault struct {
+	Code    int
+	Message string
+}
+
+func (f *LoopiaFault) Error() string {
+	return fmt.Sprintf("loopia fault %d: %s", f.Code, f.Message)
+}
+
+// Is reports whether target is the sentinel error f.Message classifies as,
+// so errors.Is(err, api.ErrRateLimited) works against a LoopiaFault the same
+// way it would against any ordinary sentinel error.
+func (f *LoopiaFault) Is(target error) bool {
+	classified := classifyFaultMessage(f.Message)
+	return classified != nil && classified == target
+}
+
+// classifyFaultMessage maps a Loopia XML-RPC fault message to the sentinel
+// error it represents, or nil if the message isn't one we recognize.
+func classifyFaultMessage(message string) error {
+	switch strings.ToUpper(message) {
+	case "AUTH_ERROR":
+		return ErrUnauthorized
+	case "RATE_LIMITED":
+		return ErrRateLimited
+	case "UNAVAILABLE", "DOMAIN_UNAVAILABLE":
+		return ErrDomainUnavailable
+	case "INSUFFICIENT_FUNDS", "INSUFFICIENT_CREDITS":
+		return ErrInsufficientCredits
+	case "UNKNOWN_INVOICE", "INVOICE_NOT_FOUND":
+		return ErrInvoiceNotFound
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// domainLimiter gives each domain its own quota of Loopia's ~60-calls-per-hour
+// budget instead of a single shared counter, so one hot domain being retried
+// aggressively can't use up another's quota. A domain's quota is capacity
+// times its priority (default 1 for domains with no explicit entry) divided
+// by the sum of the priorities listed in priorities. Domains without an
+// entry are not part of that sum, so with no priorities configured every
+// domain's quota is the full capacity, and the limiter does not cap the
+// combined calls of all domains.
+type domainLimiter struct {
+	mu            sync.Mutex
+	capacity      int
+	window        time.Duration
+	windowStart   time.Time
+	priorities    map[string]int
+	totalPriority int
+	callsByDomain map[string]int
+}
+
+// newDomainLimiter returns a limiter allowing capacity calls per window,
+// shared across domains according to priorities.
+func newDomainLimiter(capacity int, window time.Duration, priorities map[string]int) *domainLimiter {
+	total := 0
+	for _, p := range priorities {
+		if p > 0 {
+			total += p
+		}
+	}
+	return &domainLimiter{
+		capacity:      capacity,
+		window:        window,
+		windowStart:   time.Now(),
+		priorities:    priorities,
+		totalPriority: total,
+		callsByDomain: make(map[string]int),
+	}
+}
+
+// quota returns domainName's share of capacity for the current window,
+// rounded down but never less than 1.
+func (l *domainLimiter) quota(domainName string) int {
+	p := l.priorities[domainName]
+	if p <= 0 {
+		p = 1
+	}
+	total := l.totalPriority
+	if total <= 0 {
+		total = p
+	}
+	share := l.capacity * p / total
+	if share < 1 {
+		share = 1
+	}
+	return share
+}
+
+// seed overwrites the limiter's window start and per-domain call counts,
+// e.g. to resume accounting from a persisted journal after a restart.
+func (l *domainLimiter) seed(callsByDomain map[string]int, windowStart time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.windowStart = windowStart
+	l.callsByDomain = callsByDomain
+}
+
+// allow reports whether domainName may make another call in the current
+// window and, if so, counts it against that domain's quota. The window (and
+// every domain's counter) resets once window has elapsed since it started.
+func (l *domainLimiter) allow(domainName string) (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.windowStart) >= l.window {
+		l.windowStart = now
+		l.callsByDomain = make(map[string]int)
+	}
+
+	quota := l.quota(domainName)
+	if l.callsByDomain[domainName] >= quota {
+		return false, quota
+	}
+	l.callsByDomain[domainName]++
+	return true, quota
+}
+
+// remaining reports how many more calls domainName may make in the current
+// window, for the loopia_rate_limit_remaining metric.
+func (l *domainLimiter) remaining(domainName string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	quota := l.quota(domainName)
+	used := l.callsByDomain[domainName]
+	if used >= quota {
+		return 0
+	}
+	return quota - used
+}
+
+// totalRemaining reports how many more calls the client as a whole may make
+// in the current window, across all domains combined, for the
+// loopia_api_calls_remaining_this_hour metric.
+func (l *domainLimiter) totalRemaining() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	used := 0
+	for _, n := range l.callsByDomain {
+		used += n
+	}
+	remaining := l.capacity - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining
+}
+
+// rateLimitError returned when a domain has exhausted its weighted share of
+// the call budget for the current window.
+func rateLimitError(domainName string, quota int, window time.Duration) error {
+	return fmt.Errorf("rate limit exceeded for domain %s (quota %d calls per %s)", domainName, quota, window)
+}
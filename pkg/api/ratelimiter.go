@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter paces outgoing API calls. Wait blocks until the caller may
+// proceed or ctx is done, whichever comes first; Allow reports whether a
+// call may proceed right now without blocking (the default token bucket
+// takes a token when it reports true). Call paces itself with Wait only.
+// Injectable via WithRateLimiter so tests can swap in a fake that
+// always/never allows.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+	Allow() bool
+}
+
+// tokenBucketLimiter is the default RateLimiter: a token bucket that
+// refills at a sustained rate but allows a short burst above it, so a
+// scheduler can fire several rapid retries at the drop moment instead of
+// being hard-rejected the instant an hourly counter rolls over.
+type tokenBucketLimiter struct {
+	limiter *rate.Limiter
+}
+
+// newTokenBucketLimiter returns a RateLimiter refilling at r with room for
+// burst calls above the sustained rate.
+func newTokenBucketLimiter(r rate.Limit, burst int) *tokenBucketLimiter {
+	return &tokenBucketLimiter{limiter: rate.NewLimiter(r, burst)}
+}
+
+func (t *tokenBucketLimiter) Wait(ctx context.Context) error { return t.limiter.Wait(ctx) }
+func (t *tokenBucketLimiter) Allow() bool                    { return t.limiter.Allow() }
+
+// defaultCallBurst lets the scheduler fire a handful of rapid retries at
+// the drop moment while the sustained rate still averages under Loopia's
+// documented 60-calls-per-hour limit.
+const defaultCallBurst = 5
+
+// perHour converts n calls per hour into a rate.Limit (calls per second).
+func perHour(n int) rate.Limit {
+	return rate.Limit(float64(n) / 3600.0)
+}
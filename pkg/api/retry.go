@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how Call retries a failed attempt: up to MaxAttempts
+// total tries, spaced by a fully-jittered exponential backoff bounded by
+// BaseDelay/MaxDelay (or a server-provided Retry-After, if present), and
+// only for errors ShouldRetry classifies as transient.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	ShouldRetry func(err error) bool
+}
+
+// DefaultRetryPolicy retries transient failures (429, 5xx, network errors)
+// up to 4 times total, backing off from 1s up to 30s, and gives up
+// immediately on terminal ones (401, an "unavailable" XML-RPC fault).
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   1 * time.Second,
+		MaxDelay:    30 * time.Second,
+		ShouldRetry: defaultShouldRetry,
+	}
+}
+
+// nextDelay returns how long to wait before the attempt after attempt,
+// honoring a server-provided Retry-After on a 429 and otherwise falling
+// back to fully-jittered exponential backoff.
+func (p RetryPolicy) nextDelay(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	return fullJitterBackoff(p.BaseDelay, p.MaxDelay, attempt)
+}
+
+// defaultShouldRetry distinguishes transient errors (429, 5xx, a network
+// failure with no HTTP status at all) from terminal ones (401 Unauthorized,
+// a LoopiaFault reporting the domain itself is unavailable or credentials
+// are bad) that no amount of retrying will fix.
+func defaultShouldRetry(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrDomainUnavailable) ||
+		errors.Is(err, ErrInsufficientCredits) || errors.Is(err, ErrInvoiceNotFound) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode != http.StatusUnauthorized
+	}
+
+	return true
+}
+
+// fullJitterBackoff returns a random delay in [0, min(maxDelay,
+// base*2^attempt)), spreading out concurrent retries instead of having them
+// collide in lockstep ("full jitter":
+// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/).
+func fullJitterBackoff(base, maxDelay time.Duration, attempt int) time.Duration {
+	upper := base
+	for i := 0; i < attempt && upper < maxDelay; i++ {
+		upper *= 2
+		if upper <= 0 { // overflow
+			upper = maxDelay
+			break
+		}
+	}
+	if upper > maxDelay {
+		upper = maxDelay
+	}
+	if upper <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(upper)))
+}
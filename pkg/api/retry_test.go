@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFullJitterBackoff(t *testing.T) {
+	base := 1 * time.Second
+	maxDelay := 30 * time.Second
+
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration // exclusive upper bound
+	}{
+		{"attempt 0 stays at base", 0, base},
+		{"attempt 1 doubles", 1, 2 * base},
+		{"attempt 2 doubles again", 2, 4 * base},
+		{"attempt well past maxDelay is capped", 20, maxDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				got := fullJitterBackoff(base, maxDelay, tt.attempt)
+				if got < 0 || got >= tt.want {
+					t.Fatalf("fullJitterBackoff(%s, %s, %d) = %s, want in [0, %s)", base, maxDelay, tt.attempt, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFullJitterBackoffNeverExceedsMaxDelay(t *testing.T) {
+	base := 500 * time.Millisecond
+	maxDelay := 2 * time.Second
+
+	for attempt := 0; attempt < 64; attempt++ {
+		for i := 0; i < 10; i++ {
+			got := fullJitterBackoff(base, maxDelay, attempt)
+			if got > maxDelay {
+				t.Fatalf("fullJitterBackoff(%s, %s, %d) = %s, exceeds maxDelay", base, maxDelay, attempt, got)
+			}
+		}
+	}
+}
+
+func TestRetryPolicyNextDelayHonorsRetryAfter(t *testing.T) {
+	p := DefaultRetryPolicy()
+	err := &APIError{
+		StatusCode: http.StatusTooManyRequests,
+		RetryAfter: 7 * time.Second,
+		Method:     "orderDomain",
+		Err:        errors.New("429"),
+	}
+
+	if got := p.nextDelay(1, err); got != 7*time.Second {
+		t.Errorf("nextDelay with Retry-After = %s, want 7s", got)
+	}
+}
+
+func TestRetryPolicyNextDelayFallsBackToJitteredBackoffWithoutRetryAfter(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 1 * time.Second, MaxDelay: 30 * time.Second}
+
+	// No Retry-After on the 429.
+	err := &APIError{StatusCode: http.StatusTooManyRequests, Method: "orderDomain", Err: errors.New("429")}
+	if got := p.nextDelay(1, err); got < 0 || got >= 2*p.BaseDelay {
+		t.Errorf("nextDelay without Retry-After = %s, want in [0, %s)", got, 2*p.BaseDelay)
+	}
+
+	// A non-429 error never looks at RetryAfter even if somehow set.
+	err = &APIError{StatusCode: http.StatusInternalServerError, RetryAfter: 99 * time.Second, Method: "orderDomain", Err: errors.New("500")}
+	if got := p.nextDelay(1, err); got >= 99*time.Second {
+		t.Errorf("nextDelay for a 500 used RetryAfter = %s, want bounded by backoff", got)
+	}
+}
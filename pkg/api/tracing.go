@@ -0,0 +1,19 @@
+package api
+
+import (
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies this package's instrumentation scope to whatever
+// TracerProvider it reports spans to.
+const tracerName = "github.com/uberswe/LoopiaDomainBackorder/pkg/api"
+
+// WithTracerProvider sets the trace.TracerProvider Call uses to create a
+// span for every XML-RPC method it invokes (named after the method, e.g.
+// "orderDomain", "getDomain", "payInvoiceUsingCredits"), tagged with
+// domain, HTTP status, rate-limit quota and duration, so a single
+// registration attempt's spans can be correlated end to end in Jaeger or
+// Tempo. The global otel.GetTracerProvider() is used if this is never set.
+func WithTracerProvider(tp trace.TracerProvider) ClientOption {
+	return func(c *Client) { c.tracer = tp.Tracer(tracerName) }
+}
@@ -0,0 +1,57 @@
+// Package clock abstracts away direct calls to the time package so that
+// time-dependent logic (the dropcatch wait loop, the "wait until firstShot,
+// then fire fast-retry, then exponential backoff" state machine) can be
+// driven by a fake clock in tests instead of the real wall clock.
+package clock
+
+import "time"
+
+// Ticker mirrors the subset of *time.Ticker used by this codebase.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Clock is the interface time-dependent code should depend on instead of
+// calling time.Now, time.After, time.NewTicker or time.Sleep directly.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+	Sleep(d time.Duration)
+}
+
+// systemClock is the real, wall-clock-backed Clock implementation.
+type systemClock struct{}
+
+// NewSystemClock returns a Clock backed by the real time package.
+func NewSystemClock() Clock { return systemClock{} }
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (systemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return &systemTicker{t: time.NewTicker(d)}
+}
+
+// systemTicker wraps a *time.Ticker to satisfy the Ticker interface.
+type systemTicker struct{ t *time.Ticker }
+
+func (s *systemTicker) C() <-chan time.Time { return s.t.C }
+func (s *systemTicker) Stop()               { s.t.Stop() }
+
+// OffsetClock wraps another Clock, applying a fixed offset to Now() — e.g.
+// an NTP-measured correction for local clock skew (see pkg/ntp) — while
+// delegating After/Sleep/NewTicker, which only depend on relative
+// durations rather than absolute time, to the underlying Clock.
+type OffsetClock struct {
+	Clock
+	offset time.Duration
+}
+
+// NewOffsetClock returns a Clock whose Now() is base.Now() shifted by offset.
+func NewOffsetClock(base Clock, offset time.Duration) *OffsetClock {
+	return &OffsetClock{Clock: base, offset: offset}
+}
+
+func (o *OffsetClock) Now() time.Time { return o.Clock.Now().Add(o.offset) }
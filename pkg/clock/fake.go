@@ -0,0 +1,133 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// FakeClock is a Clock implementation that only advances when Advance is
+// called, letting tests move virtual time forward instantly instead of
+// sleeping in real time. Modeled on github.com/jonboulle/clockwork.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter represents a single After/NewTicker subscriber waiting for now
+// to reach deadline. Tickers set repeat>0 and get rescheduled after firing.
+type fakeWaiter struct {
+	deadline time.Time
+	repeat   time.Duration
+	ch       chan time.Time
+	stopped  bool
+}
+
+// NewFakeClock returns a FakeClock starting at the current wall-clock time.
+func NewFakeClock() *FakeClock {
+	return NewFakeClockAt(time.Now())
+}
+
+// NewFakeClockAt returns a FakeClock starting at t.
+func NewFakeClockAt(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+// Now returns the clock's current virtual time.
+func (f *FakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After returns a channel that fires once the virtual clock reaches now+d.
+func (f *FakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{deadline: f.now.Add(d), ch: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, w)
+	return w.ch
+}
+
+// NewTicker returns a Ticker that fires every d of virtual time.
+func (f *FakeClock) NewTicker(d time.Duration) Ticker {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{deadline: f.now.Add(d), repeat: d, ch: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, w)
+	return &fakeTicker{clock: f, waiter: w}
+}
+
+// Sleep blocks the calling goroutine until the virtual clock has advanced by d.
+func (f *FakeClock) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// Advance moves the virtual clock forward by d, firing any waiters whose
+// deadline has been reached (and rescheduling tickers).
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	now := f.now
+
+	var remaining []*fakeWaiter
+	for _, w := range f.waiters {
+		if w.stopped {
+			continue
+		}
+		if !w.deadline.After(now) {
+			select {
+			case w.ch <- now:
+			default:
+			}
+			if w.repeat > 0 {
+				w.deadline = now.Add(w.repeat)
+				remaining = append(remaining, w)
+			}
+			continue
+		}
+		remaining = append(remaining, w)
+	}
+	f.waiters = remaining
+	f.mu.Unlock()
+}
+
+// nextDeadline returns the soonest pending waiter deadline, used by tests
+// that want to advance exactly to the next scheduled event.
+func (f *FakeClock) nextDeadline() (time.Time, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.waiters) == 0 {
+		return time.Time{}, false
+	}
+	deadlines := make([]time.Time, 0, len(f.waiters))
+	for _, w := range f.waiters {
+		if !w.stopped {
+			deadlines = append(deadlines, w.deadline)
+		}
+	}
+	if len(deadlines) == 0 {
+		return time.Time{}, false
+	}
+	sort.Slice(deadlines, func(i, j int) bool { return deadlines[i].Before(deadlines[j]) })
+	return deadlines[0], true
+}
+
+func (f *FakeClock) stop(w *fakeWaiter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.stopped = true
+}
+
+// fakeTicker adapts a fakeWaiter to the Ticker interface.
+type fakeTicker struct {
+	clock  *FakeClock
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.waiter.ch }
+func (t *fakeTicker) Stop()               { t.clock.stop(t.waiter) }
@@ -0,0 +1,47 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClockNextDeadline(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFakeClockAt(start)
+
+	if _, ok := f.nextDeadline(); ok {
+		t.Fatal("nextDeadline on a clock with no waiters should report false")
+	}
+
+	f.After(5 * time.Minute)
+	f.After(1 * time.Minute)
+	ticker := f.NewTicker(10 * time.Minute)
+
+	deadline, ok := f.nextDeadline()
+	if !ok {
+		t.Fatal("nextDeadline should report true once waiters exist")
+	}
+	if want := start.Add(1 * time.Minute); !deadline.Equal(want) {
+		t.Errorf("nextDeadline = %v, want %v (the soonest of the three waiters)", deadline, want)
+	}
+
+	// Advancing exactly to the soonest deadline fires it and leaves the rest
+	// pending, so the next-soonest deadline becomes the new minimum.
+	f.Advance(1 * time.Minute)
+	deadline, ok = f.nextDeadline()
+	if !ok {
+		t.Fatal("nextDeadline should still report true after the soonest waiter fires")
+	}
+	if want := start.Add(5 * time.Minute); !deadline.Equal(want) {
+		t.Errorf("nextDeadline after advancing to the first deadline = %v, want %v", deadline, want)
+	}
+
+	// Once the ticker is stopped and the last After waiter has fired, no
+	// deadline is pending (Advance drops the stopped ticker's waiter).
+	ticker.Stop()
+	f.Advance(4 * time.Minute)
+	deadline, ok = f.nextDeadline()
+	if ok {
+		t.Errorf("nextDeadline = %v, ok=true, want false once the only remaining waiter is stopped", deadline)
+	}
+}
@@ -5,66 +5,158 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 	"github.com/uberswe/LoopiaDomainBackorder/pkg/domain"
+	"gopkg.in/yaml.v3"
 )
 
 // DefaultConfigFileName is the default name for the configuration file
 const DefaultConfigFileName = "config.json"
 
-// Load loads the configuration from the config file.
-// If the file doesn't exist, it returns a default configuration.
+// envVarPattern matches ${ENV_VAR} references inside a config file so they
+// can be interpolated before the file is parsed.
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Load loads the configuration from a single config file. If the file
+// doesn't exist, it returns a default configuration populated from
+// environment variables. It is a thin convenience wrapper around LoadMulti.
 func Load(configFileName string) (*domain.Config, error) {
+	return LoadMulti([]string{configFileName})
+}
+
+// LoadMulti loads and merges configuration from one or more files, applied
+// in order so later files override fields set by earlier ones. Both YAML
+// (.yml/.yaml) and JSON files are supported, detected from the file
+// extension; JSON is assumed for any other extension for backward
+// compatibility. ${ENV_VAR} references anywhere in a file are interpolated
+// from the environment before parsing.
+func LoadMulti(configFileNames []string) (*domain.Config, error) {
 	// Default configuration
 	config := &domain.Config{
-		Username:      os.Getenv("LOOPIA_USERNAME"),
-		Password:      os.Getenv("LOOPIA_PASSWORD"),
-		Domains:       []string{},
-		CacheDir:      "cache",
-		CachedLists:   make(map[string]string),
-		LastCacheTime: "",
+		Username: os.Getenv("LOOPIA_USERNAME"),
+		Password: os.Getenv("LOOPIA_PASSWORD"),
+		Domains:  []string{},
+		CacheDir: "cache",
+	}
+
+	loadedAny := false
+	for _, configFileName := range configFileNames {
+		fileConfig, err := loadFile(configFileName)
+		if err != nil {
+			return nil, err
+		}
+		if fileConfig == nil {
+			continue
+		}
+		loadedAny = true
+		mergeConfig(config, fileConfig)
+	}
+
+	if !loadedAny {
+		log.Warn().Strs("files", configFileNames).Msg("No configuration files found, using environment variables")
 	}
 
-	// Check if config file exists
+	return config, nil
+}
+
+// loadFile reads and parses a single config file, interpolating environment
+// variables first. It returns (nil, nil) if the file doesn't exist.
+func loadFile(configFileName string) (*domain.Config, error) {
 	if _, err := os.Stat(configFileName); os.IsNotExist(err) {
-		log.Warn().Str("file", configFileName).Msg("Configuration file not found, using environment variables")
-		return config, nil
+		return nil, nil
 	}
 
-	// Read config file
 	data, err := os.ReadFile(configFileName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, fmt.Errorf("failed to read config file %s: %w", configFileName, err)
 	}
 
-	// Parse config file
+	data = interpolateEnv(data)
+
 	var fileConfig domain.Config
-	if err := json.Unmarshal(data, &fileConfig); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	if isYAML(configFileName) {
+		if err := yaml.Unmarshal(data, &fileConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config file %s: %w", configFileName, err)
+		}
+	} else {
+		if err := json.Unmarshal(data, &fileConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON config file %s: %w", configFileName, err)
+		}
 	}
 
-	// Merge with defaults, ensuring backward compatibility
-	if fileConfig.Username != "" {
-		config.Username = fileConfig.Username
+	return &fileConfig, nil
+}
+
+// isYAML reports whether configFileName should be parsed as YAML based on
+// its extension. Anything else falls back to JSON.
+func isYAML(configFileName string) bool {
+	switch strings.ToLower(filepath.Ext(configFileName)) {
+	case ".yml", ".yaml":
+		return true
+	default:
+		return false
 	}
-	if fileConfig.Password != "" {
-		config.Password = fileConfig.Password
+}
+
+// interpolateEnv replaces every ${ENV_VAR} occurrence in data with the
+// value of that environment variable, leaving it untouched if unset.
+func interpolateEnv(data []byte) []byte {
+	return envVarPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+		name := envVarPattern.FindSubmatch(match)[1]
+		if value, ok := os.LookupEnv(string(name)); ok {
+			return []byte(value)
+		}
+		return match
+	})
+}
+
+// mergeConfig merges non-zero fields of src into dst, preserving backward
+// compatibility with the original single-file JSON loader's behaviour.
+func mergeConfig(dst, src *domain.Config) {
+	if src.Username != "" {
+		dst.Username = src.Username
 	}
-	if len(fileConfig.Domains) > 0 {
-		config.Domains = fileConfig.Domains
+	if src.Password != "" {
+		dst.Password = src.Password
 	}
-	if fileConfig.CacheDir != "" {
-		config.CacheDir = fileConfig.CacheDir
+	if len(src.Domains) > 0 {
+		dst.Domains = src.Domains
 	}
-	if fileConfig.CachedLists != nil {
-		config.CachedLists = fileConfig.CachedLists
+	if src.CacheDir != "" {
+		dst.CacheDir = src.CacheDir
 	}
-	if fileConfig.LastCacheTime != "" {
-		config.LastCacheTime = fileConfig.LastCacheTime
+	if len(src.Sources) > 0 {
+		dst.Sources = src.Sources
+	}
+	if src.SourceCacheTimes != nil {
+		dst.SourceCacheTimes = src.SourceCacheTimes
+	}
+	if src.SourceHTTPCache != nil {
+		dst.SourceHTTPCache = src.SourceHTTPCache
+	}
+	if src.SourceConcurrency != 0 {
+		dst.SourceConcurrency = src.SourceConcurrency
+	}
+	if src.TLDs != nil {
+		dst.TLDs = src.TLDs
+	}
+	if src.DomainPriorities != nil {
+		dst.DomainPriorities = src.DomainPriorities
+	}
+	if !reflect.DeepEqual(src.Notifications, domain.Notifications{}) {
+		dst.Notifications = src.Notifications
+	}
+	if !reflect.DeepEqual(src.Registrar, domain.RegistrarConfig{}) {
+		dst.Registrar = src.Registrar
+	}
+	if !reflect.DeepEqual(src.IDNA, domain.IDNAConfig{}) {
+		dst.IDNA = src.IDNA
 	}
-
-	return config, nil
 }
 
 // Save saves the configuration to the config file
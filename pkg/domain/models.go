@@ -5,12 +5,152 @@ import "time"
 
 // Config represents the configuration file structure
 type Config struct {
-	Username      string            `json:"username"`
-	Password      string            `json:"password"`
-	Domains       []string          `json:"domains"`
-	CacheDir      string            `json:"cache_dir"`
-	CachedLists   map[string]string `json:"cached_lists"`
-	LastCacheTime string            `json:"last_cache_time"`
+	Username      string               `json:"username" yaml:"username"`
+	Password      string               `json:"password" yaml:"password"`
+	Domains       []string             `json:"domains" yaml:"domains"`
+	CacheDir      string               `json:"cache_dir" yaml:"cache_dir"`
+	Notifications Notifications        `json:"notifications" yaml:"notifications"`
+	TLDs          map[string]TLDConfig `json:"tlds" yaml:"tlds"`
+	// DomainPriorities weights how a domain's share of LoopiaClient's
+	// 60-calls/hour budget is computed relative to other domains being
+	// chased concurrently; domains not listed default to priority 1. A
+	// domain with priority 3 gets roughly 3x the call budget of one with
+	// priority 1, so a single hot domain can't starve the others.
+	DomainPriorities map[string]int `json:"domain_priorities" yaml:"domain_priorities"`
+	// Registrar selects which backend (see pkg/registrar) handles each
+	// domain's TLD, so e.g. .se can be sniped via Loopia and .dk via a
+	// different provider in a single run.
+	Registrar RegistrarConfig `json:"registrar" yaml:"registrar"`
+	// IDNA configures how internationalized domain names are normalized
+	// before scoring (see util.EvaluateDomain).
+	IDNA IDNAConfig `json:"idna" yaml:"idna"`
+	// Sources configures which pkg/source ingestion sources feed the
+	// available command, beyond the default Internetstiftelsen bardate
+	// lists. Empty falls back to those two bardate lists for compatibility.
+	Sources []SourceConfig `json:"sources" yaml:"sources"`
+	// SourceCacheTimes tracks, per source (keyed by its CacheKey()), when it
+	// was last fetched, so each source can be cached and invalidated
+	// independently instead of sharing one LastCacheTime.
+	SourceCacheTimes map[string]string `json:"source_cache_times" yaml:"source_cache_times"`
+	// SourceHTTPCache tracks, per source, the ETag/Last-Modified of its last
+	// successful fetch, so conditional-GET-capable sources (see
+	// source.ConditionalFetcher) can skip re-downloading and re-parsing
+	// unchanged data.
+	SourceHTTPCache map[string]HTTPCacheMeta `json:"source_http_cache" yaml:"source_http_cache"`
+	// SourceConcurrency caps how many sources are fetched in parallel.
+	// Defaults to 4 when unset.
+	SourceConcurrency int `json:"source_concurrency" yaml:"source_concurrency"`
+}
+
+// HTTPCacheMeta holds the conditional-GET validators from a source's last
+// successful fetch.
+type HTTPCacheMeta struct {
+	ETag         string `json:"etag,omitempty" yaml:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty" yaml:"last_modified,omitempty"`
+}
+
+// SourceConfig configures a single pkg/source ingestion source.
+type SourceConfig struct {
+	// Type selects the backend: "bardate", "rdap", "zonediff", or "axfr".
+	Type string `json:"type" yaml:"type"`
+	// URL is the list/API endpoint for "bardate" and "rdap", or the
+	// zone-file download URL for "zonediff".
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// Zone is the DNS zone name for "zonediff" and "axfr" (e.g. "se").
+	Zone string `json:"zone,omitempty" yaml:"zone,omitempty"`
+	// Server is the nameserver to query for "axfr" (e.g. "a.ns.se").
+	Server string `json:"server,omitempty" yaml:"server,omitempty"`
+}
+
+// IDNAConfig selects which IDNA processing rules normalize internationalized
+// domain names.
+type IDNAConfig struct {
+	// Transitional selects UTS #46 transitional processing (matching older
+	// IDNA2003-era clients, e.g. mapping German "ß" to "ss") instead of the
+	// default non-transitional UTS #46 processing. Has no effect when
+	// RawPunycode is set.
+	Transitional bool `json:"transitional" yaml:"transitional"`
+	// RawPunycode selects golang.org/x/net/idna's Punycode profile instead
+	// of UTS #46: plain punycode encode/decode with a minimum of validation
+	// and no nameprep/stringprep normalization (case-folding, width
+	// mapping, etc.) of any kind. Despite the name, x/net/idna's Punycode
+	// profile is NOT an implementation of IDNA2003 — that library exposes
+	// no such profile — so this is only useful against a registry that
+	// wants the raw label passed through untouched, not one that actually
+	// requires IDNA2003 semantics.
+	RawPunycode bool `json:"raw_punycode" yaml:"raw_punycode"`
+}
+
+// RegistrarConfig selects and configures the pkg/registrar backends used to
+// order and pay for domains.
+type RegistrarConfig struct {
+	// Backends maps a TLD (without the leading dot, e.g. "se") to the
+	// backend name that should handle it ("loopia" or "generic"). TLDs
+	// absent from this map default to "loopia".
+	Backends map[string]string      `json:"backends" yaml:"backends"`
+	Generic  GenericRegistrarConfig `json:"generic" yaml:"generic"`
+}
+
+// GenericRegistrarConfig configures pkg/registrar's REST/JSON backend for a
+// second, non-Loopia Nordic registrar.
+type GenericRegistrarConfig struct {
+	BaseURL string `json:"base_url" yaml:"base_url"`
+	APIKey  string `json:"api_key" yaml:"api_key"`
+}
+
+// TLDConfig holds per-TLD overrides for the drop schedule. TLDs that aren't
+// listed fall back to util.DropHourUTC (04:00 UTC, the .se/.nu default).
+type TLDConfig struct {
+	DropHourUTC int `json:"drop_hour_utc" yaml:"drop_hour_utc"`
+}
+
+// Notifications configures the notification endpoints used to report
+// registration outcomes and heartbeats while dropcatch is running.
+type Notifications struct {
+	Ntfy              NtfyConfig   `json:"ntfy" yaml:"ntfy"`
+	Gotify            GotifyConfig `json:"gotify" yaml:"gotify"`
+	Webhooks          []string     `json:"webhooks" yaml:"webhooks"`
+	HeartbeatInterval string       `json:"heartbeat_interval" yaml:"heartbeat_interval"` // duration string, e.g. "10m"; empty uses util.TimeRecheckInterval
+	// Hooks configures additional webhook/SMTP/desktop notifiers, each
+	// independently filterable to only fire on success, only on failure, or
+	// both (the default).
+	Hooks []HookConfig `json:"hooks" yaml:"hooks"`
+}
+
+// HookConfig configures a single pkg/notify hook.
+type HookConfig struct {
+	// Type selects the backend: "webhook", "smtp", or "desktop".
+	Type string `json:"type" yaml:"type"`
+	// Endpoint is the webhook URL, or the SMTP server's "host:port".
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	// Secret is the webhook's HMAC-SHA256 signing secret, or the SMTP
+	// account's password. Unused by the desktop backend.
+	Secret string `json:"secret" yaml:"secret"`
+	// From and To are the SMTP sender and recipient addresses. Unused by
+	// the webhook and desktop backends.
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+	// Username is the SMTP auth username. Unused by the webhook and desktop backends.
+	Username string `json:"username" yaml:"username"`
+	// On restricts which outcomes fire this hook: "success", "failure", or
+	// "both" (the default, used when On is empty).
+	On string `json:"on" yaml:"on"`
+}
+
+// NtfyConfig configures the ntfy.sh notifier.
+type NtfyConfig struct {
+	Enabled   bool   `json:"enabled" yaml:"enabled"`
+	ServerURL string `json:"server_url" yaml:"server_url"`
+	Topic     string `json:"topic" yaml:"topic"`
+	Priority  string `json:"priority" yaml:"priority"`
+}
+
+// GotifyConfig configures the Gotify notifier.
+type GotifyConfig struct {
+	Enabled   bool   `json:"enabled" yaml:"enabled"`
+	ServerURL string `json:"server_url" yaml:"server_url"`
+	Token     string `json:"token" yaml:"token"`
+	Priority  int    `json:"priority" yaml:"priority"`
 }
 
 // Result represents the result of a domain registration attempt
@@ -22,21 +162,26 @@ type Result struct {
 
 // DomainInfo represents information about a domain
 type DomainInfo struct {
-	Name             string
-	ExpiryDate       time.Time
-	Length           int
-	TLD              string     // Top-level domain (.com, .se, etc.)
-	HasDash          bool       // Whether the domain contains dashes
-	IsLetterOnly     bool       // Whether the domain contains only letters
-	IsLetterNumber   bool       // Whether the domain follows letter+number pattern
-	
+	Name           string
+	ExpiryDate     time.Time
+	Length         int
+	TLD            string // Public suffix without the leading dot ("com", "se", "co.uk", etc.)
+	HasDash        bool   // Whether the domain contains dashes
+	IsLetterOnly   bool   // Whether the domain contains only letters
+	IsLetterNumber bool   // Whether the domain follows letter+number pattern
+
+	// IDNA forms of Name (see util.EvaluateDomain). For an already-ASCII,
+	// lowercase domain these are identical to Name.
+	ALabel string // Punycode (ASCII-compatible) form, e.g. "xn--mgbh0fb.se"
+	ULabel string // Unicode form, e.g. "مثال.se"
+
 	// Scoring components
-	LengthScore      float64    // Score based on domain length (0-1)
-	DashPenalty      float64    // Penalty for domains with dashes (0-1)
-	TLDScore         float64    // Score based on TLD preference (0-1)
-	KeywordScore     float64    // Score based on keyword value (0-1)
-	Pronounceable    float64    // Score based on pronounceability (0-1)
-	BrandabilityScore float64   // Score based on brandability factors (0-1)
-	
-	Score            float64    // Overall score (weighted combination)
-}
\ No newline at end of file
+	LengthScore       float64 // Score based on domain length (0-1)
+	DashPenalty       float64 // Penalty for domains with dashes (0-1)
+	TLDScore          float64 // Score based on TLD preference (0-1)
+	KeywordScore      float64 // Score based on keyword value (0-1)
+	Pronounceable     float64 // Score based on pronounceability (0-1)
+	BrandabilityScore float64 // Score based on brandability factors (0-1)
+
+	Score float64 // Overall score (weighted combination)
+}
@@ -0,0 +1,159 @@
+// Package fixture records and replays XML-RPC call transcripts for
+// api.Client's dry-run mode, so contributors can reproduce an exact
+// sequence of Loopia responses (e.g. the failures seen the day a domain
+// dropped) without live credentials, and drive retry/backoff tests against
+// it. Transcripts only ever hold the domain-facing params, never the
+// prepended username/password, so fixtures are safe to share.
+package fixture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Entry records a single call/response pair.
+type Entry struct {
+	Method   string        `json:"method"`
+	Params   []interface{} `json:"params"`
+	Response interface{}   `json:"response"`
+	Error    string        `json:"error,omitempty"`
+	DelayMs  int           `json:"delay_ms"`
+}
+
+// MatchStrategy controls how a recorded Entry is matched against an
+// incoming call.
+type MatchStrategy string
+
+const (
+	// MatchExact requires method and every param to match exactly.
+	MatchExact MatchStrategy = "exact"
+	// MatchMethodOnly matches on method alone, ignoring params.
+	MatchMethodOnly MatchStrategy = "method-only"
+	// MatchFirstArg matches on method and only the first param (typically
+	// the domain name), ignoring the rest.
+	MatchFirstArg MatchStrategy = "first-arg-only"
+)
+
+// Replay plays back a recorded transcript, matching each call's
+// (method, params) against Strategy and consuming entries in order so a
+// repeated call progresses through the transcript instead of always
+// returning the first match.
+type Replay struct {
+	mu       sync.Mutex
+	entries  []Entry
+	strategy MatchStrategy
+}
+
+// LoadReplay reads the transcript at path for later playback via Next.
+func LoadReplay(path string, strategy MatchStrategy) (*Replay, error) {
+	entries, err := readEntries(path)
+	if err != nil {
+		return nil, fmt.Errorf("fixture: load replay %s: %w", path, err)
+	}
+	return &Replay{entries: entries, strategy: strategy}, nil
+}
+
+// Next finds and consumes the first unconsumed entry matching method and
+// params according to r.strategy, sleeping delay_ms to simulate network
+// latency before returning. It errors if nothing in the transcript matches.
+func (r *Replay) Next(method string, params []interface{}) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, e := range r.entries {
+		if !matches(e, method, params, r.strategy) {
+			continue
+		}
+		r.entries = append(r.entries[:i], r.entries[i+1:]...)
+
+		if e.DelayMs > 0 {
+			time.Sleep(time.Duration(e.DelayMs) * time.Millisecond)
+		}
+		if e.Error != "" {
+			return nil, errors.New(e.Error)
+		}
+		return e.Response, nil
+	}
+	return nil, fmt.Errorf("fixture: no replay entry matches %s %v", method, params)
+}
+
+// matches reports whether e should be returned for a call to method with params.
+func matches(e Entry, method string, params []interface{}, strategy MatchStrategy) bool {
+	if e.Method != method {
+		return false
+	}
+	switch strategy {
+	case MatchMethodOnly:
+		return true
+	case MatchFirstArg:
+		if len(e.Params) == 0 || len(params) == 0 {
+			return len(e.Params) == len(params)
+		}
+		return reflect.DeepEqual(e.Params[0], params[0])
+	default: // MatchExact
+		return reflect.DeepEqual(e.Params, params)
+	}
+}
+
+// Recorder appends every real call/response to a transcript file as it happens.
+type Recorder struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewRecorder creates a Recorder appending to path, which is created if it
+// doesn't already exist.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{path: path}
+}
+
+// Record appends one call/response pair to the transcript. callErr's
+// message (if any) is stored rather than the error itself, since errors
+// aren't JSON-serializable.
+func (r *Recorder) Record(method string, params []interface{}, response interface{}, callErr error, delay time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := readEntries(r.path)
+	if err != nil {
+		return fmt.Errorf("fixture: record to %s: %w", r.path, err)
+	}
+
+	entry := Entry{Method: method, Params: params, Response: response, DelayMs: int(delay.Milliseconds())}
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	}
+	entries = append(entries, entry)
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fixture: record to %s: %w", r.path, err)
+	}
+	return os.WriteFile(r.path, data, 0644)
+}
+
+// readEntries reads the transcript at path, returning (nil, nil) if it
+// doesn't exist yet or is empty.
+func readEntries(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,92 @@
+// Package history persists the outcome of past domain registration attempts
+// so that a long-running scheduler does not re-attempt domains it has
+// already won.
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultFileName is the name of the history file stored inside the cache dir.
+const DefaultFileName = "history.json"
+
+// Record represents the outcome of a single registration attempt.
+type Record struct {
+	Domain    string    `json:"domain"`
+	Success   bool      `json:"success"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// History tracks registration attempts across process restarts.
+type History struct {
+	mu      sync.Mutex
+	path    string
+	Records []Record `json:"records"`
+}
+
+// Load reads the history file at path. If it doesn't exist, an empty
+// History backed by that path is returned.
+func Load(path string) (*History, error) {
+	h := &History{path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, err
+	}
+	h.path = path
+
+	return h, nil
+}
+
+// Save writes the history to disk, creating its parent directory if needed.
+func (h *History) Save() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(h.path, data, 0644)
+}
+
+// Won reports whether domainName has already been registered successfully.
+func (h *History) Won(domainName string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, r := range h.Records {
+		if r.Domain == domainName && r.Success {
+			return true
+		}
+	}
+	return false
+}
+
+// Record appends the outcome of an attempt for domainName.
+func (h *History) Record(domainName string, success bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.Records = append(h.Records, Record{
+		Domain:    domainName,
+		Success:   success,
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,142 @@
+// Package journal persists a line-delimited record of every domain
+// registration attempt (not just the final outcome, see pkg/history) so a
+// crashed or restarted dropcatch process can resume a drop window: skip
+// domains already won and carry over the calls already spent against the
+// hourly API budget instead of starting a fresh one.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFileName is the name of the journal file stored inside the cache dir.
+const DefaultFileName = "journal.jsonl"
+
+// Entry records a single registration attempt.
+type Entry struct {
+	Timestamp  time.Time     `json:"timestamp"`
+	Domain     string        `json:"domain"`
+	Attempt    int           `json:"attempt"`
+	Method     string        `json:"method,omitempty"`
+	Duration   time.Duration `json:"duration"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Success    bool          `json:"success"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// Journal appends Entry records to a JSON-lines file as attempts happen.
+type Journal struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// Open opens (creating if needed) the journal file at path for appending.
+func Open(path string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{path: path, file: f}, nil
+}
+
+// Append writes e to the journal as a single JSON line.
+func (j *Journal) Append(e Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = j.file.Write(data)
+	return err
+}
+
+// Close closes the underlying journal file.
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+// ReadAll reads every entry currently in the journal file at path. It
+// returns (nil, nil) if the file doesn't exist yet.
+func ReadAll(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Reset deletes the journal file at path, clearing any resume state. It is
+// not an error if the file doesn't exist.
+func Reset(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// WonDomains returns the set of domains with at least one successful entry.
+func WonDomains(entries []Entry) map[string]bool {
+	won := make(map[string]bool)
+	for _, e := range entries {
+		if e.Success {
+			won[e.Domain] = true
+		}
+	}
+	return won
+}
+
+// Since returns the subset of entries recorded at or after t.
+func Since(entries []Entry, t time.Time) []Entry {
+	var recent []Entry
+	for _, e := range entries {
+		if !e.Timestamp.Before(t) {
+			recent = append(recent, e)
+		}
+	}
+	return recent
+}
+
+// CallCountsByDomain returns how many entries belong to each domain, used to
+// seed the API client's rate limiter so a resumed run doesn't forget calls
+// already spent in the current window.
+func CallCountsByDomain(entries []Entry) map[string]int {
+	counts := make(map[string]int)
+	for _, e := range entries {
+		counts[e.Domain]++
+	}
+	return counts
+}
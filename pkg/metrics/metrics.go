@@ -0,0 +1,140 @@
+// Package metrics exposes Prometheus metrics for the dropcatch command so
+// that an unattended sniping process can be monitored and alerted on.
+package metrics
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// AttemptsTotal counts every registration attempt made against the API.
+	AttemptsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dropcatch_attempts_total",
+		Help: "Total number of domain registration attempts.",
+	})
+
+	// SuccessesTotal counts successful registrations.
+	SuccessesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dropcatch_successes_total",
+		Help: "Total number of successful domain registrations.",
+	})
+
+	// FailuresTotal counts failed attempts, labeled by failure reason.
+	FailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dropcatch_failures_total",
+		Help: "Total number of failed domain registration attempts.",
+	}, []string{"reason"})
+
+	// AttemptDuration observes how long each registration attempt took.
+	AttemptDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dropcatch_attempt_duration_seconds",
+		Help:    "Duration of individual domain registration attempts.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// TimeToFirstSuccess observes how long it took from firstShot until a
+	// domain was successfully registered.
+	TimeToFirstSuccess = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "dropcatch_time_to_first_success_seconds",
+		Help:    "Time elapsed between firstShot and a successful registration.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// SecondsUntilNextDrop reports the countdown to the next drop time.
+	SecondsUntilNextDrop = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dropcatch_seconds_until_next_drop",
+		Help: "Seconds remaining until the next scheduled drop time.",
+	})
+
+	// APICallsTotal counts every Loopia API call, labeled by XML-RPC method
+	// and resulting status ("ok", or an HTTP status code on failure).
+	APICallsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "loopia_api_calls_total",
+		Help: "Total number of Loopia API calls, labeled by method and status.",
+	}, []string{"method", "status"})
+
+	// APICallDuration observes how long each Loopia API call took, labeled
+	// by XML-RPC method.
+	APICallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "loopia_api_call_duration_seconds",
+		Help:    "Duration of individual Loopia API calls.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// AttemptTotal counts registration attempts per domain, labeled by
+	// outcome ("success" or "failure").
+	AttemptTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "loopia_attempt_total",
+		Help: "Total number of domain registration attempts, labeled by domain and outcome.",
+	}, []string{"domain", "outcome"})
+
+	// AttemptSeconds observes how long each registration attempt took,
+	// labeled by domain.
+	AttemptSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "loopia_attempt_seconds",
+		Help:    "Duration of individual domain registration attempts, labeled by domain.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"domain"})
+
+	// RateLimitRemaining reports how many API calls a domain has left in the
+	// current rate-limit window before it hits its quota.
+	RateLimitRemaining = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "loopia_rate_limit_remaining",
+		Help: "Remaining API calls in the current rate-limit window, labeled by domain.",
+	}, []string{"domain"})
+
+	// NextDropTimestamp reports the Unix timestamp of the next scheduled drop.
+	NextDropTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "loopia_next_drop_timestamp_seconds",
+		Help: "Unix timestamp of the next scheduled drop time.",
+	})
+
+	// APICallsRemainingThisHour reports how many Loopia API calls remain in
+	// the current hourly window across all domains combined, so an
+	// unattended daemon can alert before it runs out rather than after.
+	APICallsRemainingThisHour = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "loopia_api_calls_remaining_this_hour",
+		Help: "Remaining Loopia API calls in the current hourly window, across all domains.",
+	})
+
+	// DomainOrderAttemptsTotal counts every domain order attempt, labeled by
+	// result ("success" or "failure").
+	DomainOrderAttemptsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "loopia_domain_order_attempts_total",
+		Help: "Total number of domain order attempts, labeled by result.",
+	}, []string{"result"})
+
+	// InvoicePaymentsTotal counts every invoice payment attempt, labeled by
+	// result ("success" or "failure").
+	InvoicePaymentsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "loopia_invoice_payments_total",
+		Help: "Total number of invoice payment attempts, labeled by result.",
+	}, []string{"result"})
+)
+
+// StartServer starts an HTTP server exposing /metrics on addr. It runs in
+// the background and is shut down when ctx is cancelled.
+func StartServer(ctx context.Context, addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		log.Info().Str("addr", addr).Msg("Starting Prometheus metrics server")
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error().Err(err).Msg("Metrics server stopped unexpectedly")
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+}
@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/domain"
+)
+
+// FromConfig builds a Fanout from the notification endpoints enabled in cfg.
+// It returns an empty Fanout (a no-op) if nothing is enabled.
+func FromConfig(cfg domain.Notifications) *Fanout {
+	var notifiers []Notifier
+
+	if cfg.Ntfy.Enabled {
+		notifiers = append(notifiers, NewNtfy(cfg.Ntfy.ServerURL, cfg.Ntfy.Topic, cfg.Ntfy.Priority))
+	}
+	if cfg.Gotify.Enabled {
+		notifiers = append(notifiers, NewGotify(cfg.Gotify.ServerURL, cfg.Gotify.Token, cfg.Gotify.Priority))
+	}
+	for _, url := range cfg.Webhooks {
+		notifiers = append(notifiers, NewWebhook(url))
+	}
+	for _, hook := range cfg.Hooks {
+		n := hookNotifier(hook)
+		if n == nil {
+			continue
+		}
+		notifiers = append(notifiers, newOutcomeFilter(n, hook.On))
+	}
+
+	return NewFanout(notifiers...)
+}
+
+// hookNotifier builds the Notifier hook.Type selects, or nil (logging a
+// warning) if hook.Type isn't recognized.
+func hookNotifier(hook domain.HookConfig) Notifier {
+	switch hook.Type {
+	case "webhook":
+		if hook.Secret != "" {
+			return NewSignedWebhook(hook.Endpoint, hook.Secret)
+		}
+		return NewWebhook(hook.Endpoint)
+	case "smtp":
+		return NewSMTP(hook.Endpoint, hook.Username, hook.Secret, hook.From, hook.To)
+	case "desktop":
+		return NewDesktop()
+	default:
+		log.Warn().Str("type", hook.Type).Msg("Unknown notification hook type, skipping")
+		return nil
+	}
+}
@@ -0,0 +1,21 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/gen2brain/beeep"
+)
+
+// Desktop is a Notifier that shows an OS-native desktop notification.
+type Desktop struct{}
+
+// NewDesktop creates a Desktop notifier.
+func NewDesktop() *Desktop {
+	return &Desktop{}
+}
+
+// Notify implements Notifier. ctx is accepted for interface compatibility;
+// beeep has no context support of its own.
+func (d *Desktop) Notify(ctx context.Context, event Event) error {
+	return beeep.Notify(titleFor(event), messageFor(event), "")
+}
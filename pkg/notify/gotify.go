@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Gotify is a Notifier that pushes events to a Gotify server's message API.
+type Gotify struct {
+	ServerURL string // e.g. https://gotify.example.com
+	Token     string // application token
+	Priority  int
+	Client    *http.Client
+}
+
+// NewGotify creates a Gotify notifier for the given server and app token.
+func NewGotify(serverURL, token string, priority int) *Gotify {
+	return &Gotify{ServerURL: strings.TrimSuffix(serverURL, "/"), Token: token, Priority: priority, Client: http.DefaultClient}
+}
+
+type gotifyMessage struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+// Notify implements Notifier.
+func (g *Gotify) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(gotifyMessage{
+		Title:    titleFor(event),
+		Message:  messageFor(event),
+		Priority: g.Priority,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/message?token=%s", g.ServerURL, g.Token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("gotify notify: unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// StartHeartbeat periodically sends an EventHeartbeat through notifier until
+// ctx is cancelled. It is intended to run in its own goroutine alongside the
+// dropcatch wait loop so users know the catcher is still alive and waiting.
+func StartHeartbeat(ctx context.Context, notifier Notifier, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			event := Event{Type: EventHeartbeat, Message: "Dropcatch is alive and waiting for the drop", Timestamp: time.Now()}
+			if err := notifier.Notify(ctx, event); err != nil {
+				log.Warn().Err(err).Msg("Failed to send heartbeat notification")
+			}
+		}
+	}
+}
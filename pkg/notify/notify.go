@@ -0,0 +1,81 @@
+// Package notify provides a pluggable notification subsystem so that users
+// running the dropcatch command unattended can learn immediately whether a
+// registration attempt succeeded, failed, or whether the catcher is still
+// alive and waiting.
+package notify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// notifyTimeout bounds how long any single notifier in a Fanout gets before
+// it's given up on, so one slow or broken endpoint can't delay the others.
+const notifyTimeout = 10 * time.Second
+
+// EventType identifies the kind of event being reported.
+type EventType string
+
+const (
+	// EventSuccess is emitted when a domain was registered successfully.
+	EventSuccess EventType = "success"
+	// EventFailure is emitted when a domain registration attempt failed.
+	EventFailure EventType = "failure"
+	// EventHeartbeat is emitted periodically while waiting for the drop to confirm the catcher is alive.
+	EventHeartbeat EventType = "heartbeat"
+)
+
+// Event describes something worth notifying a user about.
+type Event struct {
+	Type      EventType
+	Domain    string
+	Message   string
+	Timestamp time.Time
+}
+
+// Notifier delivers an Event to some external channel (ntfy, Gotify, a
+// generic webhook, ...). Implementations should treat ctx as a per-call
+// deadline and must not block indefinitely.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Fanout delivers an Event to every configured Notifier. An error from one
+// endpoint doesn't prevent the others from being notified; Notify reports
+// the last error it saw to the caller.
+type Fanout struct {
+	Notifiers []Notifier
+}
+
+// NewFanout creates a Fanout over the given notifiers.
+func NewFanout(notifiers ...Notifier) *Fanout {
+	return &Fanout{Notifiers: notifiers}
+}
+
+// Notify sends event to every notifier in the fanout concurrently, each
+// bounded by notifyTimeout, so a slow or broken notifier can't delay or
+// block delivery to the others. It returns the last error encountered, if any.
+func (f *Fanout) Notify(ctx context.Context, event Event) error {
+	errs := make([]error, len(f.Notifiers))
+
+	var wg sync.WaitGroup
+	for i, n := range f.Notifiers {
+		wg.Add(1)
+		go func(i int, n Notifier) {
+			defer wg.Done()
+			notifyCtx, cancel := context.WithTimeout(ctx, notifyTimeout)
+			defer cancel()
+			errs[i] = n.Notify(notifyCtx, event)
+		}(i, n)
+	}
+	wg.Wait()
+
+	var lastErr error
+	for _, err := range errs {
+		if err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
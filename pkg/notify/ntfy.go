@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Ntfy is a Notifier that publishes events to an ntfy.sh (or self-hosted
+// ntfy) topic. See https://ntfy.sh for the wire format.
+type Ntfy struct {
+	ServerURL string // e.g. https://ntfy.sh
+	Topic     string
+	Priority  string // low, default, high, urgent - empty uses ntfy's default
+	Client    *http.Client
+}
+
+// NewNtfy creates an Ntfy notifier for the given server and topic.
+func NewNtfy(serverURL, topic, priority string) *Ntfy {
+	return &Ntfy{ServerURL: strings.TrimSuffix(serverURL, "/"), Topic: topic, Priority: priority, Client: http.DefaultClient}
+}
+
+// Notify implements Notifier.
+func (n *Ntfy) Notify(ctx context.Context, event Event) error {
+	url := fmt.Sprintf("%s/%s", n.ServerURL, n.Topic)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(messageFor(event)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", titleFor(event))
+	if n.Priority != "" {
+		req.Header.Set("Priority", n.Priority)
+	}
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("ntfy notify: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func titleFor(event Event) string {
+	switch event.Type {
+	case EventSuccess:
+		return "Domain registered"
+	case EventFailure:
+		return "Domain registration failed"
+	default:
+		return "Dropcatch heartbeat"
+	}
+}
+
+func messageFor(event Event) string {
+	if event.Message != "" {
+		return event.Message
+	}
+	return event.Domain
+}
@@ -0,0 +1,40 @@
+package notify
+
+import "context"
+
+// outcomeFilter wraps a Notifier so it only sees the events HookConfig.On
+// allows it to fire for. Heartbeats always pass through unfiltered, since
+// On only governs registration outcomes (success/failure).
+type outcomeFilter struct {
+	Notifier
+	allowSuccess bool
+	allowFailure bool
+}
+
+// newOutcomeFilter wraps n according to on ("success", "failure", or "both"
+// / "" for no filtering).
+func newOutcomeFilter(n Notifier, on string) Notifier {
+	switch on {
+	case "success":
+		return &outcomeFilter{Notifier: n, allowSuccess: true}
+	case "failure":
+		return &outcomeFilter{Notifier: n, allowFailure: true}
+	default:
+		return n
+	}
+}
+
+// Notify implements Notifier.
+func (f *outcomeFilter) Notify(ctx context.Context, event Event) error {
+	switch event.Type {
+	case EventSuccess:
+		if !f.allowSuccess {
+			return nil
+		}
+	case EventFailure:
+		if !f.allowFailure {
+			return nil
+		}
+	}
+	return f.Notifier.Notify(ctx, event)
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// SMTP is a Notifier that emails a short plaintext message describing the event.
+type SMTP struct {
+	Host     string // e.g. smtp.example.com:587
+	Username string
+	Password string
+	From     string
+	To       string
+}
+
+// NewSMTP creates an SMTP notifier sending from "from" to "to" via host
+// (its own "host:port"), authenticating as username/password if username is set.
+func NewSMTP(host, username, password, from, to string) *SMTP {
+	return &SMTP{Host: host, Username: username, Password: password, From: from, To: to}
+}
+
+// Notify implements Notifier. ctx is accepted for interface compatibility;
+// net/smtp has no context support of its own.
+func (s *SMTP) Notify(ctx context.Context, event Event) error {
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n", s.From, s.To, titleFor(event), messageFor(event))
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, smtpHost(s.Host))
+	}
+
+	return smtp.SendMail(s.Host, auth, s.From, []string{s.To}, []byte(msg))
+}
+
+// smtpHost strips the port from a "host:port" address, since smtp.PlainAuth
+// needs the bare hostname while smtp.SendMail wants host:port.
+func smtpHost(addr string) string {
+	if idx := strings.LastIndex(addr, ":"); idx != -1 {
+		return addr[:idx]
+	}
+	return addr
+}
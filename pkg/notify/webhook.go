@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Webhook is a Notifier that POSTs each event as JSON to an arbitrary URL.
+// If Secret is set, the payload is signed with HMAC-SHA256 and the
+// signature sent in the X-Signature-256 header so the receiver can verify
+// the request actually came from this dropcatch instance.
+type Webhook struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+// NewWebhook creates an unsigned Webhook notifier posting to url.
+func NewWebhook(url string) *Webhook {
+	return &Webhook{URL: url, Client: http.DefaultClient}
+}
+
+// NewSignedWebhook creates a Webhook notifier that signs its payload with
+// secret via HMAC-SHA256.
+func NewSignedWebhook(url, secret string) *Webhook {
+	return &Webhook{URL: url, Secret: secret, Client: http.DefaultClient}
+}
+
+// Notify implements Notifier.
+func (w *Webhook) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook notify: unexpected status %s", resp.Status)
+	}
+	return nil
+}
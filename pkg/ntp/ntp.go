@@ -0,0 +1,120 @@
+// Package ntp implements a minimal SNTP client (RFC 4330) used to measure
+// and correct local clock skew before a dropcatch drop-time fire, where
+// being a few hundred milliseconds late can mean losing a domain to a
+// faster bidder.
+package ntp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultServers are queried when the caller doesn't configure its own list.
+var DefaultServers = []string{"pool.ntp.org", "time.cloudflare.com", "time.google.com"}
+
+// maxOutlierOffset discards any single server's measured offset from Sync's
+// median if its magnitude exceeds this, so one bad reading can't skew the result.
+const maxOutlierOffset = 500 * time.Millisecond
+
+// ntpEpochOffset is the number of seconds between the NTP epoch
+// (1900-01-01) and the Unix epoch (1970-01-01).
+const ntpEpochOffset = 2208988800
+
+// ntpPort is the standard NTP port, appended to a server address that
+// doesn't already specify one.
+const ntpPort = "123"
+
+// Query performs a single SNTP round-trip against server (a host, or
+// "host:port" if a non-standard port is needed) and returns the measured
+// clock offset (add this to local time to get true time) and round-trip
+// delay, per RFC 4330's "on-wire" protocol:
+//
+//	T1 = request sent, T2 = request received by server,
+//	T3 = response sent by server, T4 = response received
+//	delay  = (T4 - T1) - (T3 - T2)
+//	offset = ((T2 - T1) + (T3 - T4)) / 2
+func Query(server string, timeout time.Duration) (offset, delay time.Duration, err error) {
+	addr := server
+	if !strings.Contains(addr, ":") {
+		addr = net.JoinHostPort(addr, ntpPort)
+	}
+
+	conn, err := net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return 0, 0, fmt.Errorf("ntp: dial %s: %w", server, err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, 0, fmt.Errorf("ntp: set deadline for %s: %w", server, err)
+	}
+
+	var req [48]byte
+	req[0] = 0x1B // LI=0 (no warning), VN=3, Mode=3 (client)
+
+	t1 := time.Now()
+	if _, err := conn.Write(req[:]); err != nil {
+		return 0, 0, fmt.Errorf("ntp: send request to %s: %w", server, err)
+	}
+
+	var resp [48]byte
+	if _, err := conn.Read(resp[:]); err != nil {
+		return 0, 0, fmt.Errorf("ntp: read response from %s: %w", server, err)
+	}
+	t4 := time.Now()
+
+	t2 := ntpToTime(resp[32:40]) // ReceiveTimestamp
+	t3 := ntpToTime(resp[40:48]) // TransmitTimestamp
+
+	delay = t4.Sub(t1) - t3.Sub(t2)
+	offset = (t2.Sub(t1) + t3.Sub(t4)) / 2
+	return offset, delay, nil
+}
+
+// ntpToTime converts an 8-byte NTP timestamp (32-bit seconds since 1900,
+// 32-bit fraction) into a time.Time.
+func ntpToTime(b []byte) time.Time {
+	secs := binary.BigEndian.Uint32(b[0:4])
+	frac := binary.BigEndian.Uint32(b[4:8])
+	nsec := int64(float64(frac) / (1 << 32) * 1e9)
+	return time.Unix(int64(secs)-ntpEpochOffset, nsec).UTC()
+}
+
+// Sync queries every server in servers, discards any whose measured offset
+// exceeds maxOutlierOffset in magnitude (or that failed outright), and
+// returns the median offset of the survivors. It errors only if none survived.
+func Sync(servers []string, timeout time.Duration) (time.Duration, error) {
+	var offsets []time.Duration
+	var errs []error
+	for _, server := range servers {
+		offset, _, err := Query(server, timeout)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if offset > maxOutlierOffset || offset < -maxOutlierOffset {
+			errs = append(errs, fmt.Errorf("ntp: discarding outlier offset %s from %s", offset, server))
+			continue
+		}
+		offsets = append(offsets, offset)
+	}
+
+	if len(offsets) == 0 {
+		return 0, fmt.Errorf("ntp: no usable offset from %d server(s): %w", len(servers), firstOrNil(errs))
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets[len(offsets)/2], nil
+}
+
+// firstOrNil returns the first error in errs, or nil if errs is empty, for
+// wrapping into a single summary error.
+func firstOrNil(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[0]
+}
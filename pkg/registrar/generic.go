@@ -0,0 +1,106 @@
+package registrar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// GenericRegistrar is a REST/JSON client for a generic EPP-over-HTTP
+// registrar backend, used for TLDs Loopia doesn't serve (e.g. a second
+// provider fronting .dk). It implements Registrar the same way
+// LoopiaRegistrar does, but speaks plain JSON over HTTPS instead of XML-RPC.
+type GenericRegistrar struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// NewGenericRegistrar returns a GenericRegistrar that talks to baseURL,
+// authenticating with apiKey as a bearer token.
+func NewGenericRegistrar(baseURL, apiKey string) *GenericRegistrar {
+	return &GenericRegistrar{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Close releases r's idle HTTP connections, satisfying shutdown.Closer.
+func (r *GenericRegistrar) Close(ctx context.Context) error {
+	r.http.CloseIdleConnections()
+	return nil
+}
+
+// domainInfoResponse is the backend's JSON representation of a domain's
+// current status, as returned by GET /v1/domains/{domain}.
+type domainInfoResponse struct {
+	Domain      string `json:"domain"`
+	ReferenceNo string `json:"reference_no"`
+}
+
+func (r *GenericRegistrar) Order(ctx context.Context, domainName string) error {
+	return r.do(ctx, http.MethodPost, "/v1/domains/orders", map[string]string{"domain": domainName}, nil)
+}
+
+func (r *GenericRegistrar) SettleInvoice(ctx context.Context, domainName string) error {
+	info, err := r.Info(ctx, domainName)
+	if err != nil {
+		return err
+	}
+	if info.ReferenceNo == "" {
+		return nil
+	}
+	return r.do(ctx, http.MethodPost, "/v1/invoices/"+info.ReferenceNo+"/pay", nil, nil)
+}
+
+func (r *GenericRegistrar) Info(ctx context.Context, domainName string) (DomainInfo, error) {
+	var resp domainInfoResponse
+	if err := r.do(ctx, http.MethodGet, "/v1/domains/"+domainName, nil, &resp); err != nil {
+		return DomainInfo{}, err
+	}
+	return DomainInfo{Domain: resp.Domain, ReferenceNo: resp.ReferenceNo}, nil
+}
+
+// do issues an HTTP request against path with an optional JSON body,
+// decoding a JSON response into out if non-nil.
+func (r *GenericRegistrar) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reqBody bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = *bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.apiKey)
+
+	log.Info().Str("method", method).Str("path", path).Msg("Sending generic registrar request")
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("generic registrar: %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("generic registrar: %s %s: unexpected status %d", method, path, resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
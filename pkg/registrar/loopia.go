@@ -0,0 +1,38 @@
+package registrar
+
+import (
+	"context"
+
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/api"
+)
+
+// LoopiaRegistrar adapts pkg/api.Client to the Registrar interface.
+type LoopiaRegistrar struct {
+	client *api.Client
+}
+
+// NewLoopiaRegistrar wraps client as a Registrar.
+func NewLoopiaRegistrar(client *api.Client) *LoopiaRegistrar {
+	return &LoopiaRegistrar{client: client}
+}
+
+func (r *LoopiaRegistrar) Order(ctx context.Context, domainName string) error {
+	return r.client.OrderDomain(ctx, domainName)
+}
+
+func (r *LoopiaRegistrar) SettleInvoice(ctx context.Context, domainName string) error {
+	return r.client.PayInvoiceIfAny(ctx, domainName)
+}
+
+func (r *LoopiaRegistrar) Info(ctx context.Context, domainName string) (DomainInfo, error) {
+	ref, err := r.client.InvoiceReference(ctx, domainName)
+	if err != nil {
+		return DomainInfo{}, err
+	}
+	return DomainInfo{Domain: domainName, ReferenceNo: ref}, nil
+}
+
+// Close delegates to the underlying api.Client, satisfying shutdown.Closer.
+func (r *LoopiaRegistrar) Close(ctx context.Context) error {
+	return r.client.Close(ctx)
+}
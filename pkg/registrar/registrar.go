@@ -0,0 +1,72 @@
+// Package registrar abstracts the domain-facing operations a dropcatch
+// attempt needs (placing an order, settling any invoice it creates,
+// checking a domain's current status) behind a Registrar interface, so
+// internal/dropcatch can snipe domains across more than one backend
+// provider (e.g. Loopia for .se/.nu, a different registrar for .dk) in a
+// single run without caring which one is behind a given domain.
+package registrar
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies this package's instrumentation scope to whatever
+// TracerProvider it reports spans to.
+const tracerName = "github.com/uberswe/LoopiaDomainBackorder/pkg/registrar"
+
+// tracer creates the top-level "registration_attempt" span Attempt wraps
+// every domain in. Unlike pkg/api.Client's tracer, which is configurable via
+// WithTracerProvider, Attempt is a package function rather than a method on
+// some struct, so it always reports to the global otel.GetTracerProvider().
+var tracer = otel.Tracer(tracerName)
+
+// DomainInfo describes a domain's current registration status as reported
+// by a backend, independent of that backend's wire format.
+type DomainInfo struct {
+	Domain      string
+	ReferenceNo string // outstanding invoice reference, if any
+}
+
+// Registrar is implemented by every backend capable of ordering and paying
+// for a domain.
+type Registrar interface {
+	// Order places a registration order for domainName.
+	Order(ctx context.Context, domainName string) error
+	// SettleInvoice pays any outstanding invoice Order left behind for
+	// domainName. It is not an error for there to be nothing to settle.
+	SettleInvoice(ctx context.Context, domainName string) error
+	// Info returns the backend's current view of domainName.
+	Info(ctx context.Context, domainName string) (DomainInfo, error)
+}
+
+// Attempt performs the order-then-settle sequence common to every backend:
+// it orders domainName and, if that succeeds, settles any invoice left
+// behind. This is what internal/dropcatch calls regardless of which
+// Registrar is behind domainName. The whole sequence is wrapped in a single
+// "registration_attempt" span, so a domain's order and settle-invoice calls
+// (and, for BackendLoopia, the underlying XML-RPC spans pkg/api.Client.Call
+// starts for each of them) all nest under one per-domain trace.
+func Attempt(ctx context.Context, r Registrar, domainName string) error {
+	ctx, span := tracer.Start(ctx, "registration_attempt", trace.WithAttributes(
+		attribute.String("domain", domainName),
+	))
+	defer span.End()
+
+	if err := r.Order(ctx, domainName); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	if err := r.SettleInvoice(ctx, domainName); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
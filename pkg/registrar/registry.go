@@ -0,0 +1,79 @@
+package registrar
+
+import (
+	"context"
+	"strings"
+
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/api"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/domain"
+)
+
+const (
+	// BackendLoopia and BackendGeneric are the backend names recognised in
+	// RegistrarConfig.Backends.
+	BackendLoopia  = "loopia"
+	BackendGeneric = "generic"
+)
+
+// Registry resolves which Registrar backend handles a given domain, based on
+// its TLD.
+type Registry struct {
+	backends map[string]Registrar
+	byTLD    map[string]string
+}
+
+// NewRegistry builds a Registry from cfg, always registering loopiaClient
+// under BackendLoopia (the default for any TLD not listed in
+// cfg.Backends), and additionally registering a GenericRegistrar under
+// BackendGeneric if cfg.Generic.BaseURL is set.
+func NewRegistry(cfg domain.RegistrarConfig, loopiaClient *api.Client) *Registry {
+	backends := map[string]Registrar{
+		BackendLoopia: NewLoopiaRegistrar(loopiaClient),
+	}
+	if cfg.Generic.BaseURL != "" {
+		backends[BackendGeneric] = NewGenericRegistrar(cfg.Generic.BaseURL, cfg.Generic.APIKey)
+	}
+	return &Registry{backends: backends, byTLD: cfg.Backends}
+}
+
+// For returns the Registrar responsible for domainName's TLD, falling back
+// to BackendLoopia if the TLD isn't listed in RegistrarConfig.Backends or
+// names an unregistered backend.
+func (r *Registry) For(domainName string) Registrar {
+	name := r.byTLD[tld(domainName)]
+	if name == "" {
+		name = BackendLoopia
+	}
+	if backend, ok := r.backends[name]; ok {
+		return backend
+	}
+	return r.backends[BackendLoopia]
+}
+
+// Close closes every backend that has cleanup to run (e.g. LoopiaRegistrar
+// and GenericRegistrar both release idle HTTP connections), satisfying
+// shutdown.Closer so a single registration covers the whole registry
+// regardless of which backends cfg enabled.
+func (r *Registry) Close(ctx context.Context) error {
+	var lastErr error
+	for _, backend := range r.backends {
+		closer, ok := backend.(interface {
+			Close(ctx context.Context) error
+		})
+		if !ok {
+			continue
+		}
+		if err := closer.Close(ctx); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// tld returns domainName's top-level domain without the leading dot.
+func tld(domainName string) string {
+	if idx := strings.LastIndex(domainName, "."); idx != -1 {
+		return domainName[idx+1:]
+	}
+	return ""
+}
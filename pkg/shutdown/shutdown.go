@@ -0,0 +1,94 @@
+// Package shutdown implements a small bounded-timeout shutdown registry, so
+// subsystems (the Loopia API client, the registrar, persistence, metrics)
+// can register cleanup callbacks that run when the application is asked to
+// stop, without a slow or hung callback delaying process exit indefinitely.
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Closer is implemented by any subsystem with cleanup to run on shutdown
+// (flushing buffers, closing idle connections, releasing file handles).
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// CloserFunc adapts a plain function to Closer.
+type CloserFunc func(ctx context.Context) error
+
+// Close calls f.
+func (f CloserFunc) Close(ctx context.Context) error { return f(ctx) }
+
+// namedCloser pairs a Closer with the name it was registered under, used
+// only to identify it in shutdown logging.
+type namedCloser struct {
+	name   string
+	closer Closer
+}
+
+// Registry collects Closers registered by independent subsystems over the
+// lifetime of a run, so a single call to Close can shut them all down
+// together within a bounded timeout.
+type Registry struct {
+	mu      sync.Mutex
+	closers []namedCloser
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds c to the registry under name, used only for logging which
+// subsystem errored or timed out during Close.
+func (r *Registry) Register(name string, c Closer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closers = append(r.closers, namedCloser{name: name, closer: c})
+}
+
+// Close runs every registered Closer concurrently, each sharing a context
+// bounded by timeout, and returns once they've all finished or timeout
+// elapses overall — whichever comes first — so a callback that ignores its
+// ctx and hangs can't delay shutdown past timeout. Errors and timeouts are
+// logged, not returned, since by the time Close is called there's nothing
+// left to do but exit.
+func (r *Registry) Close(ctx context.Context, timeout time.Duration) {
+	r.mu.Lock()
+	closers := append([]namedCloser(nil), r.closers...)
+	r.mu.Unlock()
+
+	if len(closers) == 0 {
+		return
+	}
+
+	closeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for _, nc := range closers {
+			wg.Add(1)
+			go func(nc namedCloser) {
+				defer wg.Done()
+				if err := nc.closer.Close(closeCtx); err != nil {
+					log.Warn().Err(err).Str("subsystem", nc.name).Msg("Subsystem failed to shut down cleanly")
+				}
+			}(nc)
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-closeCtx.Done():
+		log.Warn().Dur("timeout", timeout).Msg("Shutdown timeout elapsed before all subsystems finished closing; proceeding anyway")
+	}
+}
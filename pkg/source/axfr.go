@@ -0,0 +1,64 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// AXFR lists a zone's current owner names via a DNS zone transfer ("dig
+// AXFR"), for the small number of TLDs that still permit it. Like ZoneDiff,
+// a zone transfer carries no expiry information, so every returned record's
+// ExpiryDate is just the time Fetch ran, truncated to the day; callers that
+// need real expiry dates should prefer Bardate or RDAP where available.
+type AXFR struct {
+	Zone   string
+	Server string
+}
+
+// NewAXFR creates an AXFR source transferring zone from server.
+func NewAXFR(zone, server string) *AXFR {
+	return &AXFR{Zone: zone, Server: server}
+}
+
+// Name implements Source.
+func (a *AXFR) Name() string { return "axfr:" + a.Zone }
+
+// CacheKey implements Source.
+func (a *AXFR) CacheKey() string { return "axfr_" + sanitizeKey(a.Zone) }
+
+// Fetch implements Source.
+func (a *AXFR) Fetch(ctx context.Context) ([]DomainRecord, error) {
+	cmd := exec.CommandContext(ctx, "dig", "AXFR", a.Zone, "@"+a.Server)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("source: AXFR zone transfer for %s @%s failed (TLD likely doesn't permit transfers): %w", a.Zone, a.Server, err)
+	}
+
+	now := time.Now().Truncate(24 * time.Hour)
+	apex := strings.ToLower(strings.TrimSuffix(a.Zone, ".")) + "."
+
+	seen := make(map[string]bool)
+	var records []DomainRecord
+
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		name := zoneOwnerName(scanner.Text())
+		if name == "" || name+"." == apex || seen[name] {
+			continue
+		}
+		seen[name] = true
+		records = append(records, DomainRecord{Name: name, ExpiryDate: now})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("source: AXFR scan for %s: %w", a.Zone, err)
+	}
+
+	return records, nil
+}
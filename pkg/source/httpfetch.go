@@ -0,0 +1,49 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// httpRetryAttempts is how many attempts, the first try included, a request
+// gets on a network error or 5xx response before giving up.
+const httpRetryAttempts = 3
+
+// httpRetryBaseDelay is the delay before the first retry, doubling on each
+// subsequent attempt.
+const httpRetryBaseDelay = 500 * time.Millisecond
+
+// doRequest performs req via client, retrying with exponential backoff on
+// network errors and 5xx responses. 4xx responses are returned as-is since
+// retrying them wouldn't help.
+func doRequest(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	var lastErr error
+	delay := httpRetryBaseDelay
+
+	for attempt := 0; attempt < httpRetryAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= 500 {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("server error: %s", resp.Status)
+			continue
+		}
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("request to %s failed after %d attempts: %w", req.URL, httpRetryAttempts, lastErr)
+}
@@ -0,0 +1,89 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RDAP walks a registry's RDAP domain search endpoint
+// ("/domains?name=*"), reading each result's ldhName and its
+// events[eventAction="expiration"] entry.
+type RDAP struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewRDAP creates an RDAP source against baseURL (e.g.
+// "https://rdap.example-registry.se").
+func NewRDAP(baseURL string) *RDAP {
+	return &RDAP{BaseURL: baseURL, HTTPClient: http.DefaultClient}
+}
+
+// Name implements Source.
+func (r *RDAP) Name() string { return "rdap:" + r.BaseURL }
+
+// CacheKey implements Source.
+func (r *RDAP) CacheKey() string { return "rdap_" + sanitizeKey(r.BaseURL) }
+
+// rdapSearchResponse models the subset of RFC 9082's domain search response
+// this source needs.
+type rdapSearchResponse struct {
+	DomainSearchResults []rdapDomain `json:"domainSearchResults"`
+}
+
+type rdapDomain struct {
+	LDHName string      `json:"ldhName"`
+	Events  []rdapEvent `json:"events"`
+}
+
+type rdapEvent struct {
+	EventAction string `json:"eventAction"`
+	EventDate   string `json:"eventDate"`
+}
+
+// Fetch implements Source.
+func (r *RDAP) Fetch(ctx context.Context) ([]DomainRecord, error) {
+	url := strings.TrimSuffix(r.BaseURL, "/") + "/domains?name=*"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("source: rdap request for %s: %w", url, err)
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := doRequest(ctx, r.HTTPClient, req)
+	if err != nil {
+		return nil, fmt.Errorf("source: rdap fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("source: rdap fetch %s: unexpected status %s", url, resp.Status)
+	}
+
+	var search rdapSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&search); err != nil {
+		return nil, fmt.Errorf("source: rdap decode %s: %w", url, err)
+	}
+
+	var records []DomainRecord
+	for _, d := range search.DomainSearchResults {
+		for _, e := range d.Events {
+			if e.EventAction != "expiration" {
+				continue
+			}
+			expiry, err := time.Parse(time.RFC3339, e.EventDate)
+			if err != nil {
+				continue
+			}
+			records = append(records, DomainRecord{Name: d.LDHName, ExpiryDate: expiry})
+			break
+		}
+	}
+
+	return records, nil
+}
@@ -0,0 +1,42 @@
+package source
+
+import (
+	"strings"
+
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/domain"
+)
+
+// Source type identifiers used in domain.SourceConfig.Type.
+const (
+	TypeBardate  = "bardate"
+	TypeRDAP     = "rdap"
+	TypeZoneDiff = "zonediff"
+	TypeAXFR     = "axfr"
+)
+
+// FromConfig builds the enabled Sources described by cfgs, silently skipping
+// any entry with an unrecognized Type.
+func FromConfig(cfgs []domain.SourceConfig, cacheDir string) []Source {
+	sources := make([]Source, 0, len(cfgs))
+	for _, c := range cfgs {
+		switch c.Type {
+		case TypeBardate:
+			sources = append(sources, NewBardate(c.URL))
+		case TypeRDAP:
+			sources = append(sources, NewRDAP(c.URL))
+		case TypeZoneDiff:
+			sources = append(sources, NewZoneDiff(c.Zone, c.URL, cacheDir+"/"+sanitizeKey(c.Zone)+"_zone_snapshot.txt"))
+		case TypeAXFR:
+			sources = append(sources, NewAXFR(c.Zone, c.Server))
+		}
+	}
+	return sources
+}
+
+// sanitizeKey makes s safe to use as (part of) a cache file name.
+func sanitizeKey(s string) string {
+	s = strings.TrimPrefix(s, "https://")
+	s = strings.TrimPrefix(s, "http://")
+	replacer := strings.NewReplacer("/", "_", ":", "_", "?", "_", "*", "_")
+	return replacer.Replace(s)
+}
@@ -0,0 +1,44 @@
+// Package source provides pluggable ingestion of candidate expiring
+// domains, so the available command isn't hard-wired to Internetstiftelsen's
+// two .se/.nu bardate lists. Each Source knows how to fetch its own list of
+// DomainRecords and how to key its own cache entry; internal/available
+// drives one or more of them per the domain.Config.Sources setting.
+package source
+
+import (
+	"context"
+	"time"
+)
+
+// DomainRecord is a single domain name and the date it expires (or, for
+// sources that can't determine an exact date, the date it was observed to
+// have dropped out of its zone).
+type DomainRecord struct {
+	Name       string
+	ExpiryDate time.Time
+}
+
+// Source fetches a list of candidate domains from one ingestion backend.
+type Source interface {
+	// Name is a short, human-readable identifier for logging.
+	Name() string
+	// CacheKey identifies this source's cached data for per-source cache
+	// invalidation and on-disk file naming; stable across runs given the
+	// same configuration.
+	CacheKey() string
+	// Fetch retrieves this source's current list of domain records.
+	Fetch(ctx context.Context) ([]DomainRecord, error)
+}
+
+// ConditionalFetcher is implemented by Sources whose backend supports HTTP
+// conditional GET (ETag / Last-Modified), letting the caller skip both the
+// download and the re-parse when the upstream data hasn't changed.
+type ConditionalFetcher interface {
+	Source
+	// FetchConditional behaves like Fetch, but sends etag/lastModified as
+	// If-None-Match/If-Modified-Since. If the backend reports the data is
+	// unchanged (HTTP 304), notModified is true and records is nil.
+	// newETag/newLastModified should be persisted by the caller and passed
+	// back in on the next call.
+	FetchConditional(ctx context.Context, etag, lastModified string) (records []DomainRecord, newETag, newLastModified string, notModified bool, err error)
+}
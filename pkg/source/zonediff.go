@@ -0,0 +1,145 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ZoneDiff fetches a TLD's full zone file (CZDS-style: one owner name per
+// line) and diffs it against the previous run's snapshot, emitting names
+// that have dropped out of the zone since then as expiring "today". It
+// can't know a domain's actual expiry timestamp, only that it's gone, so
+// ExpiryDate on returned records is always the time Fetch ran, truncated to
+// the day.
+type ZoneDiff struct {
+	Zone string
+	// URL downloads the current zone file.
+	URL string
+	// SnapshotPath is where the previous run's zone is read from and the
+	// current one is written to, so the next run has something to diff
+	// against. A missing file is treated as an empty previous zone (so the
+	// first run after enabling this source reports nothing removed).
+	SnapshotPath string
+	HTTPClient   *http.Client
+}
+
+// NewZoneDiff creates a ZoneDiff source for zone, downloading from url and
+// keeping its snapshot at snapshotPath.
+func NewZoneDiff(zone, url, snapshotPath string) *ZoneDiff {
+	return &ZoneDiff{Zone: zone, URL: url, SnapshotPath: snapshotPath, HTTPClient: http.DefaultClient}
+}
+
+// Name implements Source.
+func (z *ZoneDiff) Name() string { return "zonediff:" + z.Zone }
+
+// CacheKey implements Source.
+func (z *ZoneDiff) CacheKey() string { return "zonediff_" + sanitizeKey(z.Zone) }
+
+// Fetch implements Source.
+func (z *ZoneDiff) Fetch(ctx context.Context) ([]DomainRecord, error) {
+	today, err := z.fetchZone(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := readZoneNames(z.SnapshotPath)
+	if err != nil {
+		return nil, fmt.Errorf("source: zonediff read previous snapshot for %s: %w", z.Zone, err)
+	}
+
+	if err := writeZoneNames(z.SnapshotPath, today); err != nil {
+		return nil, fmt.Errorf("source: zonediff write snapshot for %s: %w", z.Zone, err)
+	}
+
+	now := time.Now().Truncate(24 * time.Hour)
+	var records []DomainRecord
+	for name := range previous {
+		if !today[name] {
+			records = append(records, DomainRecord{Name: name, ExpiryDate: now})
+		}
+	}
+	return records, nil
+}
+
+// fetchZone downloads and parses z.URL into the set of owner names present.
+func (z *ZoneDiff) fetchZone(ctx context.Context) (map[string]bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, z.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("source: zonediff request for %s: %w", z.URL, err)
+	}
+
+	resp, err := doRequest(ctx, z.HTTPClient, req)
+	if err != nil {
+		return nil, fmt.Errorf("source: zonediff fetch %s: %w", z.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("source: zonediff fetch %s: unexpected status %s", z.URL, resp.Status)
+	}
+
+	names := make(map[string]bool)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if name := zoneOwnerName(scanner.Text()); name != "" {
+			names[name] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("source: zonediff scan %s: %w", z.URL, err)
+	}
+	return names, nil
+}
+
+// zoneOwnerName extracts the owner name from a zone-file resource record
+// line (the first whitespace-separated field), skipping comments, blank
+// lines, and $ directives.
+func zoneOwnerName(line string) string {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "$") {
+		return ""
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSuffix(fields[0], "."))
+}
+
+// readZoneNames reads a snapshot written by writeZoneNames, returning an
+// empty set if path doesn't exist yet.
+func readZoneNames(path string) (map[string]bool, error) {
+	names := make(map[string]bool)
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			names[name] = true
+		}
+	}
+	return names, scanner.Err()
+}
+
+// writeZoneNames persists names (one per line) to path for the next run's diff.
+func writeZoneNames(path string, names map[string]bool) error {
+	var b strings.Builder
+	for name := range names {
+		b.WriteString(name)
+		b.WriteByte('\n')
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
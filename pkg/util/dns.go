@@ -0,0 +1,87 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CanonicalName returns s in RFC 4034 canonical form for comparison: any
+// "\DDD" backslash-decimal escapes are decoded, the result is lowercased,
+// and a trailing root dot is stripped. Every ingestion path should run
+// names through this before comparing, sorting, or deduplicating them.
+func CanonicalName(s string) string {
+	s = strings.TrimSuffix(s, ".")
+	s = decodeEscapes(s)
+	return strings.ToLower(s)
+}
+
+// decodeEscapes replaces "\DDD" sequences (a backslash followed by exactly
+// three decimal digits, the zone-file escape for a raw byte) with the byte
+// they encode, leaving everything else untouched.
+func decodeEscapes(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && isDDD(s[i+1:i+4]) {
+			n, err := strconv.Atoi(s[i+1 : i+4])
+			if err == nil && n <= 255 {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// isDDD reports whether s is exactly three ASCII decimal digits.
+func isDDD(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// CompareDomain orders a and b per RFC 4034 section 6.1 canonical DNS name
+// ordering: both names are canonicalized, split into labels, and compared
+// label-by-label starting from the rightmost (most significant) label; a
+// name that's a strict prefix of the other (from the right) sorts first. It
+// returns -1, 0, or +1 like strings.Compare.
+func CompareDomain(a, b string) int {
+	la := reverseLabels(CanonicalName(a))
+	lb := reverseLabels(CanonicalName(b))
+
+	for i := 0; i < len(la) && i < len(lb); i++ {
+		if c := strings.Compare(la[i], lb[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(la) < len(lb):
+		return -1
+	case len(la) > len(lb):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// reverseLabels splits name on "." and reverses the result, so index 0 is
+// the rightmost (TLD) label.
+func reverseLabels(name string) []string {
+	labels := strings.Split(name, ".")
+	reversed := make([]string, len(labels))
+	for i, label := range labels {
+		reversed[len(labels)-1-i] = label
+	}
+	return reversed
+}
@@ -0,0 +1,50 @@
+package util
+
+import "testing"
+
+func TestCanonicalName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"already canonical", "example.com", "example.com"},
+		{"trailing dot stripped", "example.com.", "example.com"},
+		{"uppercase lowered", "EXAMPLE.com", "example.com"},
+		{"backslash-decimal escape decoded", `foo\032bar.com`, "foo bar.com"},
+		{"escape followed by more text", `a\065b.com`, "aab.com"},
+		{"malformed escape left alone", `foo\0bar.com`, `foo\0bar.com`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalName(tt.in); got != tt.want {
+				t.Errorf("CanonicalName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareDomain(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"equal names", "example.com", "example.com", 0},
+		{"equal after canonicalization", "Example.COM.", "example.com", 0},
+		{"differ in rightmost label", "example.com", "example.org", -1},
+		{"differ in rightmost label reversed", "example.org", "example.com", 1},
+		{"shorter name (strict right-hand prefix) sorts first", "com", "example.com", -1},
+		{"longer name sorts after its suffix", "example.com", "com", 1},
+		{"differs only in leftmost label", "a.example.com", "b.example.com", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompareDomain(tt.a, tt.b); got != tt.want {
+				t.Errorf("CompareDomain(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
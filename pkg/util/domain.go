@@ -3,12 +3,13 @@ package util
 import (
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/uberswe/LoopiaDomainBackorder/pkg/domain"
 )
 
 // EvaluateDomain calculates various metrics for a domain
-// 
+//
 // The scoring algorithm values domains based on:
 // 1. Length: Shorter is better, with very short domains (2-3 chars) getting the highest scores
 // 2. Pattern: Letter-only domains (e.g., dv) are better than letter+number domains (e.g., d7)
@@ -19,24 +20,33 @@ import (
 //
 // The algorithm is designed to rank domains according to the specified criteria,
 // ensuring that domains like dv.se > d7.se > dtv.se as per the requirements.
-func EvaluateDomain(domainName string) domain.DomainInfo {
-	// Extract TLD and name part
-	tld := ""
-	name := domainName
-	if idx := strings.LastIndex(domainName, "."); idx != -1 {
-		tld = domainName[idx+1:]
-		name = domainName[:idx]
-	}
+//
+// domainName is normalized to its A-label and U-label forms per idnaCfg (see
+// pkg/domain.IDNAConfig) before scoring, so internationalized entries like
+// "xn--mgbh0fb.se" or "räksmörgås.se" are measured by Unicode rune rather
+// than raw ASCII byte.
+func EvaluateDomain(domainName string, idnaCfg domain.IDNAConfig) domain.DomainInfo {
+	aLabel, uLabel := idnaLabels(domainName, idnaCfg)
+
+	// Extract the public suffix (which may be multiple labels, e.g. "co.uk")
+	// and the registrable label (SLD) to its left, so scoring treats
+	// "example.co.uk" the same way as "example.com" rather than mistaking
+	// "uk" for the TLD and "example.co" for the name. The suffix is matched
+	// against the A-label since the embedded PSL is itself ASCII/Punycode.
+	suffix, icann := PublicSuffix(aLabel)
+	name := unicodeSLD(aLabel, uLabel, suffix, domainName)
 
 	// Initialize domain info
 	info := domain.DomainInfo{
 		Name:       domainName,
+		ALabel:     aLabel,
+		ULabel:     uLabel,
 		ExpiryDate: time.Now().AddDate(0, 0, 1), // Default expiry date (will be overwritten)
-		Length:     len(name),
-		TLD:        tld,
+		Length:     len([]rune(name)),
+		TLD:        suffix,
 		HasDash:    strings.Contains(name, "-"),
 	}
-	
+
 	// Check for letter-only and letter+number patterns
 	info.IsLetterOnly = IsLetterOnly(name)
 	info.IsLetterNumber = IsLetterNumberPattern(name)
@@ -65,13 +75,13 @@ func EvaluateDomain(domainName string) domain.DomainInfo {
 			info.LengthScore = 0
 		}
 	}
-	
+
 	// 2. Apply pattern adjustments based on the requirements (dv.se > d7.se > dtv.se)
 	// Letter-only domains are better than letter+number domains, which are better than longer domains
-	
+
 	// Flag to track if we've set brandability score directly
 	brandabilityScoreSet := false
-	
+
 	if info.IsLetterOnly {
 		if info.Length == 2 {
 			// 2-char letter-only domains (like dv) get the highest score
@@ -113,29 +123,29 @@ func EvaluateDomain(domainName string) domain.DomainInfo {
 			brandabilityScoreSet = true
 		}
 	}
-	
+
 	// 3. Calculate dash penalty
 	if info.HasDash {
 		info.DashPenalty = 0.3 // Significant penalty for domains with dashes
 	} else {
 		info.DashPenalty = 0.0
 	}
-	
+
 	// 4. Calculate TLD score
-	info.TLDScore = CalculateTLDScore(tld)
-	
+	info.TLDScore = CalculateTLDScore(suffix, icann)
+
 	// 5. Calculate pronounceability score for brandability
 	info.Pronounceable = CalculatePronounceability(name)
-	
+
 	// 6. Calculate keyword score
 	info.KeywordScore = CalculateKeywordScore(name)
-	
+
 	// 7. Calculate brandability score (combination of length, pronounceability, and no dashes)
 	// Only calculate brandability score if it hasn't been set directly
 	if !brandabilityScoreSet {
 		info.BrandabilityScore = CalculateBrandabilityScore(info)
 	}
-	
+
 	// Calculate overall score with weighted components
 	// Weights reflect the importance of each factor
 	lengthWeight := 0.35       // Length is very important
@@ -143,14 +153,14 @@ func EvaluateDomain(domainName string) domain.DomainInfo {
 	dashPenaltyWeight := 0.15  // Dash penalty is significant
 	tldWeight := 0.15          // TLD preference matters
 	keywordWeight := 0.10      // Keywords provide a bonus
-	
+
 	// Calculate final score
 	info.Score = (info.LengthScore * lengthWeight) +
 		(info.BrandabilityScore * brandabilityWeight) -
 		(info.DashPenalty * dashPenaltyWeight) +
 		(info.TLDScore * tldWeight) +
 		(info.KeywordScore * keywordWeight)
-	
+
 	// Ensure score is between 0 and 1
 	if info.Score < 0 {
 		info.Score = 0
@@ -161,18 +171,48 @@ func EvaluateDomain(domainName string) domain.DomainInfo {
 	return info
 }
 
-// IsLetterNumberPattern checks if the domain follows valuable patterns like letter+number
+// unicodeSLD returns the Unicode form of the registrable label (SLD) to the
+// left of suffix, by locating that label's position in aLabel and reading
+// the same position out of uLabel (IDNA conversion preserves the number and
+// order of labels, only re-encoding each one). Falls back to a naive split
+// of the original domainName if aLabel and uLabel don't line up (e.g.
+// domainName is itself a public suffix).
+func unicodeSLD(aLabel, uLabel, suffix, domainName string) string {
+	aLabels := strings.Split(aLabel, ".")
+	uLabels := strings.Split(uLabel, ".")
+	suffixLabelCount := 0
+	if suffix != "" {
+		suffixLabelCount = len(strings.Split(suffix, "."))
+	}
+
+	sldIndex := len(aLabels) - suffixLabelCount - 1
+	if len(aLabels) == len(uLabels) && sldIndex >= 0 && sldIndex < len(uLabels) {
+		return uLabels[sldIndex]
+	}
+
+	if idx := strings.LastIndex(domainName, "."); idx != -1 {
+		return domainName[:idx]
+	}
+	return domainName
+}
+
+// IsLetterNumberPattern checks if the domain follows valuable patterns like
+// letter+number. Operates on runes (via unicode.IsLetter/IsDigit) so
+// internationalized SLDs are classified correctly rather than just their
+// first byte.
 func IsLetterNumberPattern(name string) bool {
+	runes := []rune(name)
+
 	// Check for patterns like single letter followed by single digit (e.g., d7)
-	if len(name) == 2 && isLetter(name[0]) && isDigit(name[1]) {
+	if len(runes) == 2 && unicode.IsLetter(runes[0]) && unicode.IsDigit(runes[1]) {
 		return true
 	}
-	
+
 	// Check for patterns like single letter followed by multiple digits (e.g., a123)
-	if len(name) >= 2 && isLetter(name[0]) {
+	if len(runes) >= 2 && unicode.IsLetter(runes[0]) {
 		allDigitsAfterFirst := true
-		for i := 1; i < len(name); i++ {
-			if !isDigit(name[i]) {
+		for i := 1; i < len(runes); i++ {
+			if !unicode.IsDigit(runes[i]) {
 				allDigitsAfterFirst = false
 				break
 			}
@@ -181,54 +221,63 @@ func IsLetterNumberPattern(name string) bool {
 			return true
 		}
 	}
-	
-	return false
-}
 
-// isLetter checks if a character is a letter
-func isLetter(c byte) bool {
-	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
-}
-
-// isDigit checks if a character is a digit
-func isDigit(c byte) bool {
-	return c >= '0' && c <= '9'
+	return false
 }
 
-// IsLetterOnly checks if a domain contains only letters (no numbers or special characters)
+// IsLetterOnly checks if a domain contains only letters (no numbers or
+// special characters). Operates on runes via unicode.IsLetter, so accented
+// or non-Latin letters (e.g. in "räksmörgås") still count as letters.
 func IsLetterOnly(name string) bool {
-	for i := 0; i < len(name); i++ {
-		if !isLetter(name[i]) {
+	for _, r := range name {
+		if !unicode.IsLetter(r) {
 			return false
 		}
 	}
 	return true
 }
 
-// CalculateTLDScore returns a score between 0 and 1 based on TLD preference
-func CalculateTLDScore(tld string) float64 {
-	// Preferred TLDs get higher scores
-	switch strings.ToLower(tld) {
+// CalculateTLDScore returns a score between 0 and 1 based on TLD preference.
+// suffix is the full public suffix as returned by PublicSuffix (e.g. "co.uk",
+// "com"); icann is false for suffixes from the PRIVATE section or with no
+// PSL rule at all, which are penalized since they're not under a registry's
+// direct, standard control.
+func CalculateTLDScore(suffix string, icann bool) float64 {
+	score := 0.5 // Other TLDs get a moderate score by default
+
+	switch strings.ToLower(suffix) {
 	case "com":
-		return 1.0 // .com is the most valuable
+		score = 1.0 // .com is the most valuable
 	case "net", "org":
-		return 0.9 // .net and .org are also valuable
+		score = 0.9 // .net and .org are also valuable
 	case "io", "co", "app", "dev":
-		return 0.85 // Tech-focused TLDs are valuable
+		score = 0.85 // Tech-focused TLDs are valuable
 	case "se", "nu":
-		return 0.8 // Swedish TLDs are valuable in this context
-	default:
-		return 0.5 // Other TLDs get a moderate score
+		score = 0.8 // Swedish TLDs are valuable in this context
+	case "co.uk", "com.au", "com.br", "net.au", "org.uk":
+		score = 0.75 // Well-known multi-label ccTLD suffixes are still desirable
+	}
+
+	if !icann {
+		// Private or unrecognized suffixes (e.g. github.io, or a TLD with no
+		// PSL rule at all) aren't a registry's own namespace, so they're
+		// penalized relative to an equivalent ICANN-managed suffix.
+		score -= 0.2
+		if score < 0 {
+			score = 0
+		}
 	}
+
+	return score
 }
 
 // CalculateKeywordScore returns a score between 0 and 1 based on keyword value
 func CalculateKeywordScore(name string) float64 {
 	// Define valuable keywords in a single line to avoid syntax issues
 	keywords := []string{"web", "app", "tech", "code", "dev", "cloud", "data", "shop", "store", "buy", "sell", "market", "online", "digital", "smart", "eco", "green", "health", "care", "med", "edu", "learn", "travel", "food", "ai", "crypt", "coin", "mine"}
-	
+
 	name = strings.ToLower(name)
-	
+
 	// Check if the domain contains any valuable keywords
 	for _, keyword := range keywords {
 		if strings.Contains(name, keyword) {
@@ -242,7 +291,7 @@ func CalculateKeywordScore(name string) float64 {
 			}
 		}
 	}
-	
+
 	return 0.0 // No valuable keywords found
 }
 
@@ -252,10 +301,10 @@ func CalculateBrandabilityScore(info domain.DomainInfo) float64 {
 	// 1. Pronounceability (easy to say)
 	// 2. Memorability (short and no dashes)
 	// 3. Uniqueness (not too generic)
-	
+
 	// Start with pronounceability as the base
 	score := info.Pronounceable
-	
+
 	// Short domains are more memorable
 	if info.Length <= 4 {
 		score += 0.3
@@ -264,24 +313,24 @@ func CalculateBrandabilityScore(info domain.DomainInfo) float64 {
 	} else if info.Length <= 8 {
 		score += 0.1
 	}
-	
+
 	// Domains with dashes are less brandable
 	if info.HasDash {
 		score -= 0.3
 	}
-	
+
 	// Letter-only domains are more brandable than letter+number
 	if info.IsLetterOnly {
 		score += 0.2
 	}
-	
+
 	// Normalize score between 0 and 1
 	if score < 0 {
 		score = 0
 	} else if score > 1 {
 		score = 1
 	}
-	
+
 	return score
 }
 
@@ -291,12 +340,16 @@ func CalculatePronounceability(name string) float64 {
 	vowels := "aeiouy"
 	consonants := "bcdfghjklmnpqrstvwxz"
 
-	name = strings.ToLower(name)
+	runes := []rune(strings.ToLower(name))
+	if len(runes) == 0 {
+		return 0
+	}
+
 	score := 0.0
 	consecutiveConsonants := 0
 
-	for i := 0; i < len(name); i++ {
-		char := string(name[i])
+	for _, r := range runes {
+		char := string(r)
 
 		if strings.Contains(vowels, char) {
 			score += 0.1
@@ -310,7 +363,7 @@ func CalculatePronounceability(name string) float64 {
 	}
 
 	// Normalize score between 0 and 1
-	score = score / float64(len(name))
+	score = score / float64(len(runes))
 	if score < 0 {
 		score = 0
 	}
@@ -0,0 +1,76 @@
+package util
+
+// IsDomainName reports whether s is a validly-shaped presentation-format
+// domain name, per RFC 1035 section 2.3.4: each label is at most 63 octets,
+// the name's wire-format encoding (each label's octets plus one length
+// octet per label plus the root octet, i.e. two more than the presentation
+// length without a trailing dot) is at most 255 octets — 253
+// presentation characters, or 254 if s ends in a trailing dot — and every
+// label is LDH (letters, digits, hyphen; hyphen not leading or trailing),
+// with a single leading underscore additionally permitted so SRV-style
+// labels like "_sip" pass. Ingestion sources can contain arbitrary junk
+// lines, so every name should pass this before being handed to
+// EvaluateDomain.
+func IsDomainName(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	maxLen := 253
+	trailingDot := len(s) > 0 && s[len(s)-1] == '.'
+	if trailingDot {
+		maxLen = 254
+	}
+	if len(s) > maxLen {
+		return false
+	}
+
+	name := s
+	if trailingDot {
+		name = name[:len(name)-1]
+	}
+	if name == "" {
+		return false
+	}
+
+	start := 0
+	for i := 0; i <= len(name); i++ {
+		if i < len(name) && name[i] != '.' {
+			continue
+		}
+		if !isValidLabel(name[start:i]) {
+			return false
+		}
+		start = i + 1
+	}
+	return true
+}
+
+// isValidLabel reports whether label is a valid LDH label of at most 63
+// octets, optionally prefixed with a single underscore.
+func isValidLabel(label string) bool {
+	if label == "" || len(label) > 63 {
+		return false
+	}
+
+	if label[0] == '_' {
+		label = label[1:]
+		if label == "" {
+			return false
+		}
+	}
+
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+// label63 and label64 are a single LDH label at exactly the RFC 1035
+// boundary (63 octets, valid) and one octet past it (64 octets, invalid).
+var (
+	label63 = strings.Repeat("a", 63)
+	label64 = strings.Repeat("a", 64)
+)
+
+func TestIsDomainName(t *testing.T) {
+	// name253 and name254 sit exactly on, and one past, the 253-character
+	// presentation-format boundary (three 63-byte labels plus dots, plus a
+	// short tail label), built from repeated labels so the boundary isn't
+	// fudged by a hand-counted string literal.
+	name253 := strings.Repeat(label63+".", 3) + strings.Repeat("a", 61)
+	name254 := strings.Repeat(label63+".", 3) + strings.Repeat("a", 62)
+
+	tests := []struct {
+		name string
+		in   string
+		want bool
+	}{
+		{"empty string", "", false},
+		{"ordinary name", "example.com", true},
+		{"trailing dot", "example.com.", true},
+		{"root alone", ".", false},
+		{"63-byte label", label63 + ".com", true},
+		{"64-byte label", label64 + ".com", false},
+		{"253-byte name at boundary", name253, true},
+		{"254-byte name without trailing dot exceeds boundary", name254, false},
+		{"254-byte name with trailing dot at boundary", name253 + ".", true},
+		{"255-byte name with trailing dot exceeds boundary", name254 + ".", false},
+		{"leading hyphen", "-abc.com", false},
+		{"trailing hyphen", "abc-.com", false},
+		{"interior hyphen", "a-b.com", true},
+		{"leading underscore", "_sip.example.com", true},
+		{"underscore-only label", "_.example.com", false},
+		{"embedded space", "exa mple.com", false},
+		{"embedded control byte", "exa\x00mple.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDomainName(tt.in); got != tt.want {
+				t.Errorf("IsDomainName(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
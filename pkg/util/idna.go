@@ -0,0 +1,37 @@
+package util
+
+import (
+	"golang.org/x/net/idna"
+
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/domain"
+)
+
+// idnaProfile returns the *idna.Profile selected by cfg: golang.org/x/net/idna's
+// bare Punycode profile (encode/decode only, no nameprep-style normalization)
+// if RawPunycode is set, otherwise UTS #46 (transitional or not per cfg).
+func idnaProfile(cfg domain.IDNAConfig) *idna.Profile {
+	if cfg.RawPunycode {
+		return idna.Punycode
+	}
+	if cfg.Transitional {
+		return idna.New(idna.MapForLookup(), idna.Transitional(true), idna.StrictDomainName(false), idna.ValidateLabels(false))
+	}
+	return idna.New(idna.MapForLookup(), idna.StrictDomainName(false), idna.ValidateLabels(false))
+}
+
+// idnaLabels returns domainName's A-label (Punycode) and U-label (Unicode)
+// forms per cfg. If domainName can't be processed as IDNA (e.g. it's
+// already garbled), both forms fall back to domainName unchanged.
+func idnaLabels(domainName string, cfg domain.IDNAConfig) (aLabel, uLabel string) {
+	profile := idnaProfile(cfg)
+
+	aLabel, err := profile.ToASCII(domainName)
+	if err != nil {
+		aLabel = domainName
+	}
+	uLabel, err = profile.ToUnicode(aLabel)
+	if err != nil {
+		uLabel = domainName
+	}
+	return aLabel, uLabel
+}
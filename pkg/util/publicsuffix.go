@@ -0,0 +1,34 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	xpublicsuffix "golang.org/x/net/publicsuffix"
+)
+
+// PublicSuffix returns domainName's public suffix (the portion that's not
+// available for registration, e.g. "co.uk" or "com") plus whether the
+// matching rule came from the ICANN section (true) or the PRIVATE section /
+// no rule at all (false). It defers to golang.org/x/net/publicsuffix, which
+// embeds the full upstream Mozilla Public Suffix List (all ICANN and PRIVATE
+// rules, regenerated from https://publicsuffix.org/list/public_suffix_list.dat
+// as part of that package's release process), so every registry Loopia deals
+// in is covered rather than just the handful this project used to hand-curate.
+func PublicSuffix(domainName string) (suffix string, icann bool) {
+	domainName = strings.ToLower(strings.TrimSuffix(domainName, "."))
+	return xpublicsuffix.PublicSuffix(domainName)
+}
+
+// EffectiveTLDPlusOne returns the registrable domain for domainName: its
+// public suffix plus the one label to the left of it (e.g.
+// "www.example.co.uk" -> "example.co.uk"). It errors if domainName has no
+// label to the left of its public suffix.
+func EffectiveTLDPlusOne(domainName string) (string, error) {
+	domainLower := strings.ToLower(strings.TrimSuffix(domainName, "."))
+	etld1, err := xpublicsuffix.EffectiveTLDPlusOne(domainLower)
+	if err != nil {
+		return "", fmt.Errorf("util: %w", err)
+	}
+	return etld1, nil
+}
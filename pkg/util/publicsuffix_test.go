@@ -0,0 +1,55 @@
+package util
+
+import "testing"
+
+func TestPublicSuffix(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		wantSuffix string
+		wantICANN  bool
+	}{
+		{"simple ICANN gTLD", "example.com", "com", true},
+		{"two-label ICANN ccTLD rule", "example.co.uk", "co.uk", true},
+		{"trailing dot stripped", "example.com.", "com", true},
+		{"mixed case lowered", "EXAMPLE.COM", "com", true},
+		{"PRIVATE section entry", "foo.github.io", "github.io", false},
+		{"unlisted TLD falls back to itself", "example.nosuchtld", "nosuchtld", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suffix, icann := PublicSuffix(tt.in)
+			if suffix != tt.wantSuffix || icann != tt.wantICANN {
+				t.Errorf("PublicSuffix(%q) = (%q, %v), want (%q, %v)", tt.in, suffix, icann, tt.wantSuffix, tt.wantICANN)
+			}
+		})
+	}
+}
+
+func TestEffectiveTLDPlusOne(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"simple domain", "example.com", "example.com", false},
+		{"subdomain", "www.example.co.uk", "example.co.uk", false},
+		{"PRIVATE section entry", "foo.github.io", "foo.github.io", false},
+		{"bare public suffix errors", "co.uk", "", true},
+		{"bare gTLD errors", "com", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EffectiveTLDPlusOne(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EffectiveTLDPlusOne(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("EffectiveTLDPlusOne(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
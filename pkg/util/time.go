@@ -3,10 +3,12 @@ package util
 
 import (
 	"context"
-	"github.com/go-vgo/robotgo"
-	"github.com/rs/zerolog/log"
 	"math/rand"
 	"time"
+
+	"github.com/go-vgo/robotgo"
+	"github.com/rs/zerolog/log"
+	"github.com/uberswe/LoopiaDomainBackorder/pkg/clock"
 )
 
 const (
@@ -22,8 +24,21 @@ const (
 
 // NextDrop returns the next date at 04:00 UTC strictly after now.
 func NextDrop(now time.Time) time.Time {
+	return NextDropForTLD(now, "", nil)
+}
+
+// NextDropForTLD returns the next drop time strictly after now for tld,
+// using dropHours[tld] as the drop hour (UTC) when present and falling back
+// to DropHourUTC otherwise. This lets operators target TLDs other than
+// .se/.nu, which don't necessarily drop at 04:00 UTC.
+func NextDropForTLD(now time.Time, tld string, dropHours map[string]int) time.Time {
+	hour := DropHourUTC
+	if h, ok := dropHours[tld]; ok {
+		hour = h
+	}
+
 	utc := now.UTC()
-	drop := time.Date(utc.Year(), utc.Month(), utc.Day(), DropHourUTC, 0, 0, 0, time.UTC)
+	drop := time.Date(utc.Year(), utc.Month(), utc.Day(), hour, 0, 0, 0, time.UTC)
 	if !utc.Before(drop) {
 		drop = drop.Add(24 * time.Hour)
 	}
@@ -56,8 +71,8 @@ func GetReferenceDate(now time.Time) time.Time {
 }
 
 // KeepAwake keeps the computer awake by simulating mouse movement every minute.
-func KeepAwake(ctx context.Context) {
-	ticker := time.NewTicker(KeepAwakeInterval)
+func KeepAwake(ctx context.Context, clk clock.Clock) {
+	ticker := clk.NewTicker(KeepAwakeInterval)
 	defer ticker.Stop()
 
 	log.Info().Msg("Starting keep-awake routine")
@@ -67,7 +82,7 @@ func KeepAwake(ctx context.Context) {
 		case <-ctx.Done():
 			log.Info().Msg("Stopping keep-awake routine")
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			x, y := robotgo.GetMousePos()
 			dx := rand.Intn(20) - 10
 			dy := rand.Intn(20) - 10 // Random value between -10 and 10